@@ -2,57 +2,108 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
 
+	"github.com/Damianko135/playground-go/internal/api"
 	"github.com/Damianko135/playground-go/internal/config"
+	"github.com/Damianko135/playground-go/internal/docs"
 	"github.com/Damianko135/playground-go/internal/handlers"
+	"github.com/Damianko135/playground-go/internal/logging"
 	"github.com/Damianko135/playground-go/internal/middleware"
+	"github.com/Damianko135/playground-go/internal/selector"
+	"github.com/Damianko135/playground-go/internal/sysstats"
+	"github.com/Damianko135/playground-go/internal/tracing"
 	"github.com/Damianko135/playground-go/internal/utils"
 	"github.com/Damianko135/playground-go/views"
 	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
 )
 
 func main() {
+	// Log JSON lines until the configured logger is set up
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Printf("❌ Failed to load configuration: %v\n", err)
-		os.Exit(1)
+		fatal("failed to load configuration", err)
 	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
-		fmt.Printf("❌ Invalid configuration: %v\n", err)
-		os.Exit(1)
+		fatal("invalid configuration", err)
+	}
+
+	// Structured logging for the server, Echo and the standard log package
+	logger := logging.New(cfg.Log, os.Stdout)
+	slog.SetDefault(logger)
+
+	// Log configuration
+	logger.Info("configuration loaded", "config", cfg)
+
+	// Export request traces and propagate trace context
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Server.Environment)
+	if err != nil {
+		fatal("failed to configure tracing", err)
 	}
 
-	// Print configuration
-	cfg.Print()
+	// Select the weather data source
+	weatherProvider, err := handlers.NewWeatherProvider(cfg.Weather)
+	if err != nil {
+		fatal("failed to configure weather provider", err)
+	}
+	handlers.SetWeatherProvider(weatherProvider)
+
+	// Sample host statistics in the background
+	samplerCtx, stopSampler := context.WithCancel(context.Background())
+	defer stopSampler()
+	statsSampler := sysstats.NewSampler(cfg.Stats.Interval, cfg.Stats.DiskPath, cfg.Stats.Retention)
+	statsSampler.Start(samplerCtx)
+	handlers.SetStatsSampler(statsSampler)
+
+	// Daily picks change at midnight in the configured time zone
+	dailyLocation, err := time.LoadLocation(cfg.Content.DailyTimezone)
+	if err != nil {
+		fatal("failed to load daily time zone", err)
+	}
+	handlers.SetSelector(selector.New(dailyLocation))
+
+	// Zones shown by the world clock unless a request lists its own
+	if err := handlers.SetClockZones(cfg.Clock.Zones); err != nil {
+		fatal("failed to load world clock zones", err)
+	}
 
-	fmt.Println("🔧 Starting Echo server...")
 	e := echo.New()
 
-	// Hide Echo banner
+	// Hide Echo banner; the start is logged below
 	e.HideBanner = true
+	e.HidePort = true
+	e.Logger = logging.NewEchoLogger(logger, os.Stdout)
+	e.StdLogger = slog.NewLogLogger(logger.Handler(), slog.LevelError)
 	e.Debug = cfg.Server.Debug
 
-	// Apply core middleware
-	e.Use(echomiddleware.Recover())
+	// Report errors as problem details, error pages or HTMX fragments
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+
+	// Metrics middleware (always enabled for monitoring). The collector
+	// belongs to this server and is served on /metrics. It comes first to
+	// see every response, including recovered panics.
+	metrics := handlers.NewMetricsCollector(cfg.Metrics.Buckets)
+	e.Use(handlers.MetricsMiddleware(metrics))
+
+	// Apply core middleware. Recover comes after tracing and logging so
+	// recovered panics are traced and logged like other errors.
 	e.Use(middleware.SecurityHeaders())
 	e.Use(middleware.RequestID())
+	e.Use(middleware.Tracing())
+	e.Use(middleware.RequestLogger(logger))
+	e.Use(middleware.Recover())
 	e.Use(middleware.ResponseTime())
 
 	// Conditional middleware based on configuration
-	if cfg.IsDevelopment() {
-		e.Use(middleware.CustomLogger())
-		fmt.Println("🐛 Debug mode enabled")
-	}
-
 	if cfg.API.EnableCORS {
 		e.Use(middleware.CORS())
 	}
@@ -63,18 +114,12 @@ func main() {
 
 	e.Use(middleware.Cache())
 
-	// Metrics middleware (always enabled for monitoring)
-	e.Use(handlers.MetricsMiddleware())
-
 	// Rate limiting for API endpoints
 	apiGroup := e.Group("/api")
 	apiGroup.Use(middleware.RateLimiter())
 	apiGroup.Use(middleware.APIKeyAuth(cfg.API.Key))
 
-	// Static files
-	fmt.Println("🔧 Setting up static file serving...")
-
-	// Custom HTMX route (with correct MIME type)
+	// Static files, with a custom HTMX route (with correct MIME type)
 	e.GET("/static/htmx.min.js", func(c echo.Context) error {
 		c.Response().Header().Set("Content-Type", "application/javascript")
 		return c.File("static/htmx.min.js")
@@ -88,35 +133,42 @@ func main() {
 	e.GET("/playground", utils.Temple(views.Playground()))
 	e.GET("/tools", utils.Temple(views.Tools()))
 
-	// Health check endpoints (if enabled)
-	if cfg.Features.EnableHealthCheck {
-		e.GET("/health", handlers.HealthCheck)
-		e.GET("/health/ready", handlers.ReadinessCheck)
-		e.GET("/health/live", handlers.LivenessCheck)
-	}
+	// Health check and metrics endpoints (if enabled)
+	system := systemRoutes(cfg.Features, metrics)
+	system.Register(e)
 
-	// Metrics endpoint (if enabled)
-	if cfg.Features.EnableMetrics {
-		e.GET("/metrics", handlers.GetMetrics)
-	}
+	// API endpoints. Serve /api/v1/* and, as an alias of v1, /api/*
+	v1 := v1Routes(middleware.ModeratorAuth(cfg.API.ModerationKey))
+	api.Mount(apiGroup, v1, v1)
+	handlers.SetAPIPrefix("/api/" + v1.Name)
 
-	// API endpoints (JSON)
-	apiGroup.GET("/weather", handlers.GetWeather)
-	apiGroup.GET("/quote", handlers.GetQuote)
-	apiGroup.GET("/stats", handlers.GetSystemStats)
-	apiGroup.GET("/palette", handlers.GetColorPalette)
-	apiGroup.GET("/joke", handlers.GetJoke)
-	apiGroup.GET("/random", handlers.GetRandomNumber)
-	apiGroup.GET("/timezones", handlers.GetTimeZones)
-
-	// HTMX endpoints (HTML fragments) - no API key required for better UX
-	e.GET("/htmx/weather", handlers.GetWeatherHTML)
-	e.GET("/htmx/quote", handlers.GetQuoteHTML)
-	e.GET("/htmx/stats", handlers.GetSystemStatsHTML)
-	e.GET("/htmx/palette", handlers.GetColorPaletteHTML)
-	e.GET("/htmx/joke", handlers.GetJokeHTML)
-	e.GET("/htmx/timezones", handlers.GetWorldClockHTML)
-	e.GET("/htmx/random", handlers.GetRandomNumberHTML)
+	// API description and explorer
+	if err := handlers.SetAPISpec(apiDocument(v1, system)); err != nil {
+		fatal("failed to generate API description", err)
+	}
+	e.GET("/api/openapi.json", handlers.GetAPISpec)
+	e.StaticFS("/docs/api/assets", docs.Assets())
+	e.GET("/docs/api", utils.Temple(views.APIDocs()))
+
+	// HTMX endpoints (HTML fragments) - no API key required for better UX.
+	// They share the API handlers, which render HTML by default here.
+	htmxGroup := e.Group("/htmx", handlers.PreferFragments)
+	htmxGroup.GET("/weather", handlers.GetWeather)
+	htmxGroup.GET("/weather/forecast", handlers.GetWeatherForecast)
+	htmxGroup.GET("/quote", handlers.GetQuote)
+	htmxGroup.GET("/stats", handlers.GetSystemStats)
+	htmxGroup.GET("/stats/sparkline", handlers.GetStatsHistory)
+	htmxGroup.GET("/palette", handlers.GetColorPalette)
+	htmxGroup.GET("/palette/export", handlers.ExportPalette)
+	htmxGroup.GET("/joke", handlers.GetJoke)
+	htmxGroup.GET("/joke/:id/punchline", handlers.GetJokePunchlineHTML)
+	// Submissions fill the moderation queue, so they are limited like the API
+	htmxGroup.POST("/jokes", handlers.SubmitJoke, middleware.RateLimiter())
+	htmxGroup.GET("/timezones", handlers.GetTimeZones)
+	htmxGroup.GET("/time/overlap", handlers.GetMeetingOverlap)
+	htmxGroup.GET("/random", handlers.GetRandomNumber)
+	htmxGroup.GET("/random/id", handlers.GetRandomID)
+	htmxGroup.GET("/random/password", handlers.GetRandomPassword)
 
 	// Configure server
 	server := &http.Server{
@@ -125,22 +177,30 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
-	// Print startup information
-	fmt.Printf("🚀 Server starting on port %s\n", cfg.Server.Port)
+	// Log startup information
+	baseURL := "http://localhost:" + cfg.Server.Port
+	endpoints := []any{
+		slog.String("playground", baseURL+"/playground"),
+		slog.String("tools", baseURL+"/tools"),
+		slog.String("api", baseURL+"/api/v1"),
+		slog.String("api_docs", baseURL+"/docs/api"),
+	}
 	if cfg.Features.EnableHealthCheck {
-		fmt.Printf("📊 Health check available at: http://localhost:%s/health\n", cfg.Server.Port)
+		endpoints = append(endpoints, slog.String("health", baseURL+"/health"))
 	}
 	if cfg.Features.EnableMetrics {
-		fmt.Printf("📈 Metrics available at: http://localhost:%s/metrics\n", cfg.Server.Port)
+		endpoints = append(endpoints, slog.String("metrics", baseURL+"/metrics"))
 	}
-	fmt.Printf("🎮 Playground available at: http://localhost:%s/playground\n", cfg.Server.Port)
-	fmt.Printf("🔧 Tools available at: http://localhost:%s/tools\n", cfg.Server.Port)
-	fmt.Printf("📡 API endpoints available at: http://localhost:%s/api/*\n", cfg.Server.Port)
+	logger.Info("server starting",
+		slog.String("port", cfg.Server.Port),
+		slog.Bool("debug", cfg.Server.Debug),
+		slog.String("tracing", cfg.Tracing.Exporter),
+		slog.Group("endpoints", endpoints...))
 
 	// Start server in a goroutine
 	go func() {
 		if err := e.StartServer(server); err != nil && err != http.ErrServerClosed {
-			e.Logger.Fatal("Server failed to start:", err)
+			fatal("server failed to start", err)
 		}
 	}()
 
@@ -149,15 +209,26 @@ func main() {
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 
-	fmt.Println("\n🛑 Shutting down server...")
+	logger.Info("shutting down server")
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := e.Shutdown(ctx); err != nil {
-		e.Logger.Fatal("Server forced to shutdown:", err)
+		fatal("server forced to shut down", err)
 	}
 
-	fmt.Println("✅ Server gracefully stopped")
+	// Flush spans of the last requests
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
+
+	logger.Info("server stopped")
+}
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Damianko135/playground-go/internal/api"
+	"github.com/Damianko135/playground-go/internal/config"
+	"github.com/Damianko135/playground-go/internal/handlers"
+	"github.com/Damianko135/playground-go/internal/openapi"
+	"github.com/Damianko135/playground-go/internal/palette"
+	"github.com/Damianko135/playground-go/internal/random"
+	"github.com/Damianko135/playground-go/internal/sysstats"
+	"github.com/labstack/echo/v4"
+)
+
+// v1Routes registers version 1 of the API. A later version can start from
+// v1.Extend("v2") and only register the routes it changes; mark retired
+// routes with Deprecate to announce them to clients.
+func v1Routes(moderatorAuth echo.MiddlewareFunc) *api.Version {
+	v1 := api.NewVersion("v1")
+
+	v1.GET("/weather", handlers.GetWeather).
+		Describe("Current weather", "Look up a place by name or by coordinates.").
+		Query("location", "string", "Place name").
+		Query("lat", "number", "Latitude, together with lon").
+		Query("lon", "number", "Longitude, together with lat").
+		QueryEnum("units", "Unit system", handlers.UnitsMetric, handlers.UnitsImperial).
+		Returns(http.StatusOK, handlers.WeatherData{})
+	v1.GET("/weather/forecast", handlers.GetWeatherForecast).
+		Describe("Daily weather forecast").
+		Query("location", "string", "Place name").
+		Query("lat", "number", "Latitude, together with lon").
+		Query("lon", "number", "Longitude, together with lat").
+		QueryEnum("units", "Unit system", handlers.UnitsMetric, handlers.UnitsImperial).
+		Query("days", "integer", "Number of days, 1 to 14").
+		Returns(http.StatusOK, handlers.WeatherForecast{})
+
+	v1.GET("/quote", handlers.GetQuote).
+		Describe("Pick a quote", "mode=daily returns the same quote all day; a seed makes the pick reproducible.").
+		Query("id", "integer", "Return this quote instead of picking one").
+		QueryEnum("mode", "Selection mode", "random", "daily").
+		Query("seed", "string", "Seed for a reproducible pick").
+		Returns(http.StatusOK, handlers.QuoteData{})
+	v1.GET("/quotes", handlers.ListQuotes).
+		Describe("List quotes").
+		Query("author", "string", "Only quotes by this author").
+		Query("tag", "string", "Only quotes with this tag").
+		Query("page", "integer", "Page number, starting at 1").
+		Query("per_page", "integer", "Quotes per page").
+		Returns(http.StatusOK, handlers.QuoteList{})
+	v1.POST("/quotes", handlers.CreateQuote).
+		Describe("Add a quote").
+		Accepts(handlers.QuoteRequest{}).
+		Returns(http.StatusCreated, handlers.QuoteData{})
+	v1.GET("/quotes/:id", handlers.GetQuoteByID).
+		Describe("Get a quote").
+		PathParam("id", "integer", "Quote ID").
+		Returns(http.StatusOK, handlers.QuoteData{})
+	v1.PUT("/quotes/:id", handlers.UpdateQuote).
+		Describe("Replace a quote").
+		PathParam("id", "integer", "Quote ID").
+		Accepts(handlers.QuoteRequest{}).
+		Returns(http.StatusOK, handlers.QuoteData{})
+	v1.DELETE("/quotes/:id", handlers.DeleteQuote).
+		Describe("Delete a quote").
+		PathParam("id", "integer", "Quote ID").
+		Returns(http.StatusNoContent, nil)
+
+	v1.GET("/stats", handlers.GetSystemStats).
+		Describe("Current system statistics").
+		Returns(http.StatusOK, map[string]interface{}{})
+	v1.GET("/stats/history", handlers.GetStatsHistory).
+		Describe("Sampled history of a system metric").
+		QueryEnum("metric", "Metric to return", sysstats.Metrics...).
+		Query("since", "string", "RFC 3339 time, Unix seconds or a duration such as 15m").
+		Returns(http.StatusOK, handlers.StatsHistory{})
+
+	v1.GET("/palette", handlers.GetColorPalette).
+		Describe("Pick a color palette", "A harmony mode generates a palette as /palette/generate does.").
+		QueryEnum("mode", "Selection or harmony mode", append([]string{"random", "daily"}, palette.Modes...)...).
+		Query("seed", "string", "Seed for a reproducible pick").
+		Query("base", "string", "Base color for generated palettes, as hex").
+		Query("count", "integer", "Number of colors in generated palettes").
+		Returns(http.StatusOK, handlers.ColorPalette{})
+	v1.GET("/palette/generate", handlers.GeneratePalette).
+		Describe("Generate a harmony palette").
+		QueryEnum("mode", "Harmony mode", palette.Modes...).
+		Query("base", "string", "Base color as hex; random when omitted").
+		Query("count", "integer", "Number of colors").
+		Returns(http.StatusOK, handlers.GeneratedPalette{})
+	v1.GET("/palette/export", handlers.ExportPalette).
+		Describe("Export a palette as a file", "The body is the palette in the requested file format.").
+		QueryEnum("format", "File format", palette.Formats...).
+		Query("colors", "string", "Comma-separated hex colors").
+		Query("preset", "string", "Name of a predefined palette").
+		Query("name", "string", "Palette name used in the file").
+		Query("base", "string", "Base color of a generated palette").
+		QueryEnum("mode", "Harmony mode of a generated palette", palette.Modes...).
+		Query("count", "integer", "Number of colors in a generated palette").
+		Returns(http.StatusOK, nil)
+
+	v1.GET("/joke", handlers.GetJoke).
+		Describe("Pick a joke").
+		Query("type", "string", "Joke category").
+		QueryEnum("mode", "Selection mode", "random", "daily").
+		Query("seed", "string", "Seed for a reproducible pick").
+		Returns(http.StatusOK, handlers.JokeData{})
+	v1.GET("/jokes/types", handlers.GetJokeTypes).
+		Describe("List joke categories").
+		Returns(http.StatusOK, handlers.JokeTypes{})
+	v1.POST("/jokes", handlers.SubmitJoke).
+		Describe("Submit a joke", "Submissions are queued for moderation.").
+		Accepts(handlers.JokeRequest{}).
+		Returns(http.StatusAccepted, handlers.JokeSubmission{})
+
+	// Joke moderation endpoints
+	v1.GET("/jokes/pending", handlers.ListPendingJokes, moderatorAuth).
+		Describe("List submissions awaiting moderation").
+		Returns(http.StatusOK, handlers.PendingJokes{}).
+		Secure("ModeratorKey")
+	v1.POST("/jokes/pending/:id/approve", handlers.ApproveJoke, moderatorAuth).
+		Describe("Publish a submission").
+		PathParam("id", "integer", "Submission ID").
+		Returns(http.StatusOK, handlers.JokeData{}).
+		Secure("ModeratorKey")
+	v1.DELETE("/jokes/pending/:id", handlers.RejectJoke, moderatorAuth).
+		Describe("Reject a submission").
+		PathParam("id", "integer", "Submission ID").
+		Returns(http.StatusNoContent, nil).
+		Secure("ModeratorKey")
+
+	v1.GET("/random", handlers.GetRandomNumber).
+		Describe("Random numbers", "With dice, rolls dice in NdM+K notation and returns DiceRolls instead.").
+		QueryEnum("type", "Number type", "int", "float").
+		Query("min", "number", "Lower bound").
+		Query("max", "number", "Upper bound").
+		Query("count", "integer", "How many numbers to draw").
+		Query("unique", "boolean", "Draw without repeats").
+		QueryEnum("distribution", "Distribution", "uniform", "normal", "exponential").
+		Query("mean", "number", "Mean of the normal distribution").
+		Query("stddev", "number", "Standard deviation of the normal distribution").
+		Query("rate", "number", "Rate of the exponential distribution").
+		Query("dice", "string", "Dice notation such as 3d6+2").
+		Returns(http.StatusOK, handlers.RandomNumbers{}).
+		Or(handlers.DiceRolls{})
+	v1.GET("/random/id", handlers.GetRandomID).
+		Describe("Random identifiers").
+		QueryEnum("kind", "Identifier kind", random.IDKinds...).
+		Query("count", "integer", "How many identifiers to generate").
+		Query("size", "integer", "Length of nanoid identifiers").
+		Returns(http.StatusOK, handlers.GeneratedIDs{})
+	v1.GET("/random/password", handlers.GetRandomPassword).
+		Describe("Random passwords or passphrases").
+		QueryEnum("kind", "Secret kind", "password", "passphrase").
+		Query("length", "integer", "Password length").
+		Query("classes", "string", "Comma-separated character classes: lower, upper, digits, symbols").
+		Query("words", "integer", "Passphrase length in words").
+		Query("separator", "string", "Passphrase word separator").
+		Query("capitalize", "boolean", "Capitalize passphrase words").
+		Query("count", "integer", "How many secrets to generate").
+		Returns(http.StatusOK, handlers.GeneratedPasswords{})
+
+	v1.GET("/timezones", handlers.GetTimeZones).
+		Describe("World clock").
+		Query("zones", "string", "Comma-separated IANA zones; the configured zones when omitted").
+		Returns(http.StatusOK, handlers.WorldClock{})
+	v1.GET("/time/convert", handlers.ConvertTime).
+		Describe("Convert a time between zones").
+		Query("time", "string", "Time to convert; now when omitted").
+		Query("from", "string", "Zone the time is given in").
+		Query("to", "string", "Comma-separated target zones").
+		Returns(http.StatusOK, handlers.TimeConversion{})
+	v1.GET("/time/overlap", handlers.GetMeetingOverlap).
+		Describe("Find meeting slots within everyone's working hours").
+		Query("participants", "string", "Comma-separated Zone or Zone@HH:MM-HH:MM entries").
+		Query("tz", "string", "Zone the slots are shown in").
+		Query("from", "string", "First day, as YYYY-MM-DD").
+		Query("to", "string", "Last day, as YYYY-MM-DD").
+		Query("weekends", "boolean", "Include weekends").
+		QueryEnum("step", "Slot step", "15m", "30m", "1h").
+		Query("duration", "string", "Meeting length as a duration such as 45m or 1h30m").
+		Returns(http.StatusOK, handlers.MeetingPlan{})
+
+	return v1
+}
+
+// systemRoutes are the health and metrics endpoints, served outside /api
+func systemRoutes(features config.FeatureConfig, metrics *handlers.MetricsCollector) *api.Routes {
+	system := &api.Routes{}
+
+	if features.EnableHealthCheck {
+		system.GET("/health", handlers.HealthCheck).
+			Describe("Health report").
+			Returns(http.StatusOK, handlers.HealthResponse{})
+		system.GET("/health/ready", handlers.ReadinessCheck).
+			Describe("Readiness probe").
+			Returns(http.StatusOK, map[string]string{})
+		system.GET("/health/live", handlers.LivenessCheck).
+			Describe("Liveness probe").
+			Returns(http.StatusOK, map[string]string{})
+	}
+
+	if features.EnableMetrics {
+		system.GET("/metrics", handlers.GetMetrics(metrics)).
+			Describe("Application metrics",
+				"An Accept header of text/plain or application/openmetrics-text, as Prometheus sends, selects the Prometheus exposition format.").
+			QueryEnum("format", "Response format", "json", "prometheus").
+			Returns(http.StatusOK, handlers.Metrics{})
+	}
+
+	return system
+}
+
+// apiDocument describes the API and system routes
+func apiDocument(v1 *api.Version, system *api.Routes) openapi.Document {
+	b := openapi.New(openapi.Info{
+		Title:   "Playground Go API",
+		Version: "1.0.0",
+		Description: "Every /api/v1 route is also served under /api. Responses are JSON by default; " +
+			"send an Accept header or a format parameter (json, xml, yaml, csv, text) for other formats.",
+	})
+	b.ErrorMediaType = handlers.ProblemContentType
+	b.ValidationError = handlers.Problem{}
+	b.Error = handlers.Problem{}
+
+	b.SecurityScheme("ApiKeyHeader", openapi.SecurityScheme{
+		Type: "apiKey", In: "header", Name: "X-API-Key",
+		Description: "Required when the server is configured with an API key",
+	}, true)
+	b.SecurityScheme("ApiKeyQuery", openapi.SecurityScheme{
+		Type: "apiKey", In: "query", Name: "api_key",
+		Description: "Alternative to the X-API-Key header",
+	}, true)
+	b.SecurityScheme("ModeratorKey", openapi.SecurityScheme{
+		Type: "apiKey", In: "header", Name: "X-Moderator-Key",
+		Description: "Moderation key for the joke queue",
+	}, false)
+
+	b.Add("/api/"+v1.Name, v1.Routes.Routes())
+	b.Add("", system.Routes())
+
+	return b.Document()
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Param documents a query or path parameter
+type Param struct {
+	Name        string
+	In          string
+	Type        string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// Route is a single API endpoint and its documentation. Body and Response
+// are example values whose types describe the request and response bodies.
+type Route struct {
+	Method      string
+	Path        string
+	Handler     echo.HandlerFunc
+	Middleware  []echo.MiddlewareFunc
+	Deprecation *Deprecation
+
+	Summary     string
+	Description string
+	Params      []Param
+	Body        interface{}
+	Status      int
+	Response    interface{}
+	OneOf       []interface{}
+	Security    []string
+}
+
+// Deprecate marks the route as deprecated
+func (r *Route) Deprecate(d Deprecation) *Route {
+	r.Deprecation = &d
+	return r
+}
+
+// Describe sets the route's summary, and optionally a longer description
+func (r *Route) Describe(summary string, description ...string) *Route {
+	r.Summary = summary
+	r.Description = strings.Join(description, " ")
+	return r
+}
+
+// Query documents an optional query parameter of type string, integer,
+// number or boolean
+func (r *Route) Query(name, typ, description string) *Route {
+	r.Params = append(r.Params, Param{Name: name, In: "query", Type: typ, Description: description})
+	return r
+}
+
+// QueryEnum documents an optional string query parameter with fixed values
+func (r *Route) QueryEnum(name, description string, values ...string) *Route {
+	r.Params = append(r.Params, Param{Name: name, In: "query", Type: "string", Description: description, Enum: values})
+	return r
+}
+
+// PathParam documents a path parameter; undocumented ones default to strings
+func (r *Route) PathParam(name, typ, description string) *Route {
+	r.Params = append(r.Params, Param{Name: name, In: "path", Type: typ, Description: description, Required: true})
+	return r
+}
+
+// Accepts documents the request body with an example value
+func (r *Route) Accepts(body interface{}) *Route {
+	r.Body = body
+	return r
+}
+
+// Returns documents the success status and an example response value. A nil
+// response means the status has no body.
+func (r *Route) Returns(status int, response interface{}) *Route {
+	r.Status = status
+	r.Response = response
+	return r
+}
+
+// Or documents another body the success response may have instead of the
+// one given to Returns
+func (r *Route) Or(response interface{}) *Route {
+	r.OneOf = append(r.OneOf, response)
+	return r
+}
+
+// Secure lists the security schemes that guard the route, on top of those
+// the document requires of every route
+func (r *Route) Secure(schemes ...string) *Route {
+	r.Security = append(r.Security, schemes...)
+	return r
+}
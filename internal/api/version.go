@@ -0,0 +1,170 @@
+// Package api keeps a registry of versioned API routes. Each version is
+// served under /api/<name>, and one version is also served directly under
+// /api as an alias for clients that do not pin a version. Routes carry the
+// documentation used to generate the OpenAPI description.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Deprecation marks a route as deprecated. Responses carry the Deprecation
+// header (RFC 9745), the Sunset header (RFC 8594) when a removal date is
+// set, and a successor-version link when there is a replacement.
+type Deprecation struct {
+	Date      time.Time
+	Sunset    time.Time
+	Successor string
+}
+
+// Router is implemented by *echo.Echo and *echo.Group
+type Router interface {
+	Add(method, path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// Routes is an ordered set of routes
+type Routes struct {
+	routes []*Route
+}
+
+// Handle registers a route, replacing an existing one with the same method
+// and path
+func (rs *Routes) Handle(method, path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *Route {
+	route := &Route{Method: method, Path: path, Handler: h, Middleware: m, Status: http.StatusOK}
+	for i, existing := range rs.routes {
+		if existing.Method == method && existing.Path == path {
+			rs.routes[i] = route
+			return route
+		}
+	}
+	rs.routes = append(rs.routes, route)
+	return route
+}
+
+// GET registers a GET route
+func (rs *Routes) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *Route {
+	return rs.Handle(http.MethodGet, path, h, m...)
+}
+
+// POST registers a POST route
+func (rs *Routes) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *Route {
+	return rs.Handle(http.MethodPost, path, h, m...)
+}
+
+// PUT registers a PUT route
+func (rs *Routes) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *Route {
+	return rs.Handle(http.MethodPut, path, h, m...)
+}
+
+// DELETE registers a DELETE route
+func (rs *Routes) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *Route {
+	return rs.Handle(http.MethodDelete, path, h, m...)
+}
+
+// Remove drops a route, typically one inherited through Extend
+func (rs *Routes) Remove(method, path string) {
+	for i, r := range rs.routes {
+		if r.Method == method && r.Path == path {
+			rs.routes = append(rs.routes[:i], rs.routes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Route returns the route with the given method and path, or nil
+func (rs *Routes) Route(method, path string) *Route {
+	for _, r := range rs.routes {
+		if r.Method == method && r.Path == path {
+			return r
+		}
+	}
+	return nil
+}
+
+// Routes returns the routes in registration order
+func (rs *Routes) Routes() []Route {
+	routes := make([]Route, len(rs.routes))
+	for i, r := range rs.routes {
+		routes[i] = *r
+	}
+	return routes
+}
+
+// Register adds the routes to router as they are, without a version prefix
+func (rs *Routes) Register(router Router) {
+	for _, r := range rs.routes {
+		router.Add(r.Method, r.Path, r.Handler, append([]echo.MiddlewareFunc{headers(r, "")}, r.Middleware...)...)
+	}
+}
+
+// Version is a named set of routes
+type Version struct {
+	Routes
+	Name string
+}
+
+// NewVersion creates an empty version
+func NewVersion(name string) *Version {
+	return &Version{Name: name}
+}
+
+// Extend creates a version with a copy of v's routes. Routes registered on
+// the new version replace those with the same method and path, so a new
+// version only lists what it changes.
+func (v *Version) Extend(name string) *Version {
+	next := NewVersion(name)
+	for _, r := range v.routes {
+		route := *r
+		next.routes = append(next.routes, &route)
+	}
+	return next
+}
+
+// Mount serves each version under group/<name>, and alias directly under
+// group. Alias responses link to the canonical versioned path.
+func Mount(group *echo.Group, alias *Version, versions ...*Version) {
+	for _, v := range versions {
+		v.Register(group.Group("/" + v.Name))
+	}
+
+	if alias == nil {
+		return
+	}
+	for _, r := range alias.routes {
+		group.Add(r.Method, r.Path, r.Handler, append([]echo.MiddlewareFunc{headers(r, "/"+alias.Name)}, r.Middleware...)...)
+	}
+}
+
+// headers sets the deprecation headers of a route and, for alias routes
+// served without a version, a canonical link to the versioned path
+func headers(r *Route, canonical string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+
+			if canonical != "" {
+				// c.Path is the matched pattern, the group prefix plus r.Path
+				prefix := strings.TrimSuffix(c.Path(), r.Path)
+				path := prefix + canonical + strings.TrimPrefix(c.Request().URL.Path, prefix)
+				header.Add("Link", fmt.Sprintf("<%s>; rel=\"canonical\"", path))
+			}
+
+			if d := r.Deprecation; d != nil {
+				header.Set("Deprecation", fmt.Sprintf("@%d", d.Date.Unix()))
+				if !d.Sunset.IsZero() {
+					header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+				}
+				if d.Successor != "" {
+					header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", d.Successor))
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"errors"
+	"log/slog"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Damianko135/playground-go/internal/utils"
@@ -11,8 +13,15 @@ import (
 // Config holds all configuration for the application
 type Config struct {
 	Server   ServerConfig
+	Log      LogConfig
 	API      APIConfig
 	Features FeatureConfig
+	Metrics  MetricsConfig
+	Tracing  TracingConfig
+	Weather  WeatherConfig
+	Stats    StatsConfig
+	Content  ContentConfig
+	Clock    ClockConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -25,12 +34,20 @@ type ServerConfig struct {
 	WriteTimeout time.Duration
 }
 
+// LogConfig holds logging configuration
+type LogConfig struct {
+	Level slog.Level
+	// Format is json or text
+	Format string
+}
+
 // APIConfig holds API-related configuration
 type APIConfig struct {
-	Key        string
-	RateLimit  int
-	EnableCORS bool
-	EnableGzip bool
+	Key           string
+	ModerationKey string
+	RateLimit     int
+	EnableCORS    bool
+	EnableGzip    bool
 }
 
 // FeatureConfig holds feature flags
@@ -40,6 +57,74 @@ type FeatureConfig struct {
 	EnableProfiling   bool
 }
 
+// MetricsConfig holds metrics configuration
+type MetricsConfig struct {
+	// Buckets are the upper bounds of the request latency histogram, in
+	// seconds
+	Buckets []float64
+}
+
+// DefaultMetricsBuckets are the latency histogram buckets used when none
+// are configured, from 5ms to 10s
+var DefaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	// Exporter is where spans are sent: none, otlp, stdout or file. The
+	// stdout exporter writes JSON lines to stderr, next to the logs.
+	Exporter string
+	// Endpoint is the OTLP/HTTP traces URL, such as
+	// http://localhost:4318/v1/traces. The standard OTEL_EXPORTER_OTLP_*
+	// variables apply when it is empty.
+	Endpoint string
+	// File receives spans as JSON lines with the file exporter
+	File        string
+	ServiceName string
+	// SampleRatio is the share of new traces that are recorded. Requests
+	// continuing a trace follow the caller's sampling decision.
+	SampleRatio float64
+}
+
+// TracingExporters are the supported values of TRACING_EXPORTER
+var TracingExporters = []string{"none", "otlp", "stdout", "file"}
+
+// WeatherConfig holds weather provider configuration
+type WeatherConfig struct {
+	Provider string
+	BaseURL  string
+	Timeout  time.Duration
+}
+
+// StatsConfig holds system statistics sampling configuration
+type StatsConfig struct {
+	Interval  time.Duration
+	DiskPath  string
+	Retention time.Duration
+}
+
+// ContentConfig holds configuration for the quote, joke and palette widgets
+type ContentConfig struct {
+	DailyTimezone string
+}
+
+// ClockConfig holds world clock configuration
+type ClockConfig struct {
+	Zones []string
+}
+
+// DefaultClockZones are the IANA time zones shown by the world clock when
+// none are configured
+var DefaultClockZones = []string{
+	"UTC",
+	"America/Los_Angeles",
+	"America/New_York",
+	"Europe/London",
+	"Europe/Amsterdam",
+	"Asia/Singapore",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	port, err := utils.GetEnvVar("PORT", "8080")
@@ -72,11 +157,34 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	defaultLogLevel := "info"
+	if debug {
+		defaultLogLevel = "debug"
+	}
+	logLevelName, err := utils.GetEnvVar("LOG_LEVEL", defaultLogLevel)
+	if err != nil {
+		return nil, err
+	}
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(logLevelName)); err != nil {
+		return nil, errors.New("environment variable LOG_LEVEL is not a valid level: " + err.Error())
+	}
+
+	logFormat, err := utils.GetEnvVar("LOG_FORMAT", "json")
+	if err != nil {
+		return nil, err
+	}
+
 	apiKey, err := utils.GetEnvVar("API_KEY", "")
 	if err != nil {
 		return nil, err
 	}
 
+	moderationKey, err := utils.GetEnvVar("MODERATION_KEY", "")
+	if err != nil {
+		return nil, err
+	}
+
 	rateLimit, err := utils.GetEnvInt("RATE_LIMIT", 20)
 	if err != nil {
 		return nil, err
@@ -107,6 +215,76 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	metricsBuckets, err := utils.GetEnvFloatList("METRICS_BUCKETS", DefaultMetricsBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	tracingExporter, err := utils.GetEnvVar("TRACING_EXPORTER", "none")
+	if err != nil {
+		return nil, err
+	}
+
+	tracingEndpoint, err := utils.GetEnvVar("TRACING_ENDPOINT", "")
+	if err != nil {
+		return nil, err
+	}
+
+	tracingFile, err := utils.GetEnvVar("TRACING_FILE", "traces.jsonl")
+	if err != nil {
+		return nil, err
+	}
+
+	tracingServiceName, err := utils.GetEnvVar("TRACING_SERVICE_NAME", "playground-go")
+	if err != nil {
+		return nil, err
+	}
+
+	tracingSampleRatio, err := utils.GetEnvFloat("TRACING_SAMPLE_RATIO", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	weatherProvider, err := utils.GetEnvVar("WEATHER_PROVIDER", "static")
+	if err != nil {
+		return nil, err
+	}
+
+	weatherBaseURL, err := utils.GetEnvVar("WEATHER_BASE_URL", "")
+	if err != nil {
+		return nil, err
+	}
+
+	weatherTimeout, err := utils.GetEnvDuration("WEATHER_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	statsInterval, err := utils.GetEnvDuration("STATS_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	statsDiskPath, err := utils.GetEnvVar("STATS_DISK_PATH", "/")
+	if err != nil {
+		return nil, err
+	}
+
+	statsRetention, err := utils.GetEnvDuration("STATS_RETENTION", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyTimezone, err := utils.GetEnvVar("DAILY_TIMEZONE", "UTC")
+	if err != nil {
+		return nil, err
+	}
+
+	clockZones, err := utils.GetEnvList("WORLD_CLOCK_ZONES", DefaultClockZones)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Port:         port,
@@ -116,17 +294,48 @@ func Load() (*Config, error) {
 			ReadTimeout:  readTimeout,
 			WriteTimeout: writeTimeout,
 		},
+		Log: LogConfig{
+			Level:  logLevel,
+			Format: logFormat,
+		},
 		API: APIConfig{
-			Key:        apiKey,
-			RateLimit:  rateLimit,
-			EnableCORS: enableCORS,
-			EnableGzip: enableGzip,
+			Key:           apiKey,
+			ModerationKey: moderationKey,
+			RateLimit:     rateLimit,
+			EnableCORS:    enableCORS,
+			EnableGzip:    enableGzip,
 		},
 		Features: FeatureConfig{
 			EnableHealthCheck: enableHealthCheck,
 			EnableMetrics:     enableMetrics,
 			EnableProfiling:   enableProfiling,
 		},
+		Metrics: MetricsConfig{
+			Buckets: metricsBuckets,
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracingExporter,
+			Endpoint:    tracingEndpoint,
+			File:        tracingFile,
+			ServiceName: tracingServiceName,
+			SampleRatio: tracingSampleRatio,
+		},
+		Weather: WeatherConfig{
+			Provider: weatherProvider,
+			BaseURL:  weatherBaseURL,
+			Timeout:  weatherTimeout,
+		},
+		Stats: StatsConfig{
+			Interval:  statsInterval,
+			DiskPath:  statsDiskPath,
+			Retention: statsRetention,
+		},
+		Content: ContentConfig{
+			DailyTimezone: dailyTimezone,
+		},
+		Clock: ClockConfig{
+			Zones: clockZones,
+		},
 	}, nil
 }
 
@@ -162,35 +371,134 @@ func (c *Config) Validate() error {
 		return errors.New("server timeouts must be positive")
 	}
 
+	// Validate log format
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		return errors.New("log format must be one of: json, text")
+	}
+
 	// Validate rate limit
 	if c.API.RateLimit < 1 {
 		return errors.New("rate limit must be at least 1")
 	}
 
+	// Validate stats sampling interval
+	if c.Stats.Interval <= 0 {
+		return errors.New("stats interval must be positive")
+	}
+	if c.Stats.Retention < c.Stats.Interval {
+		return errors.New("stats retention must be at least the stats interval")
+	}
+
+	// Validate daily selection time zone
+	if _, err := time.LoadLocation(c.Content.DailyTimezone); err != nil {
+		return errors.New("invalid DAILY_TIMEZONE: " + err.Error())
+	}
+
+	// Validate world clock time zones
+	for _, zone := range c.Clock.Zones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			return errors.New("invalid WORLD_CLOCK_ZONES entry " + zone + ": " + err.Error())
+		}
+	}
+
+	// Validate latency histogram buckets
+	for i, bucket := range c.Metrics.Buckets {
+		if bucket <= 0 || (i > 0 && bucket <= c.Metrics.Buckets[i-1]) {
+			return errors.New("METRICS_BUCKETS must be positive and increasing")
+		}
+	}
+
+	// Validate tracing exporter
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		if c.Tracing.File == "" {
+			return errors.New("TRACING_FILE is required when TRACING_EXPORTER is file")
+		}
+	default:
+		return errors.New("tracing exporter must be one of: " + strings.Join(TracingExporters, ", "))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	// Validate weather provider
+	switch c.Weather.Provider {
+	case "static":
+	case "http":
+		if c.Weather.BaseURL == "" {
+			return errors.New("WEATHER_BASE_URL is required when WEATHER_PROVIDER is http")
+		}
+	default:
+		return errors.New("weather provider must be one of: static, http")
+	}
+
 	return nil
 }
 
-// Print prints the configuration (without sensitive data)
-func (c *Config) Print() {
-	println("🔧 Configuration:")
-	println("  Server:")
-	println("    Port:", c.Server.Port)
-	println("    Host:", c.Server.Host)
-	println("    Environment:", c.Server.Environment)
-	println("    Debug:", c.Server.Debug)
-	println("    Read Timeout:", c.Server.ReadTimeout.String())
-	println("    Write Timeout:", c.Server.WriteTimeout.String())
-	println("  API:")
-	println("    Rate Limit:", c.API.RateLimit)
-	println("    Enable CORS:", c.API.EnableCORS)
-	println("    Enable Gzip:", c.API.EnableGzip)
-	if c.API.Key != "" {
-		println("    API Key: [CONFIGURED]")
-	} else {
-		println("    API Key: [NOT SET]")
-	}
-	println("  Features:")
-	println("    Health Check:", c.Features.EnableHealthCheck)
-	println("    Metrics:", c.Features.EnableMetrics)
-	println("    Profiling:", c.Features.EnableProfiling)
+// LogValue describes the configuration for logging, without secrets
+func (c *Config) LogValue() slog.Value {
+	tracing := []slog.Attr{slog.String("exporter", c.Tracing.Exporter)}
+	switch c.Tracing.Exporter {
+	case "otlp":
+		if c.Tracing.Endpoint != "" {
+			tracing = append(tracing, slog.String("endpoint", c.Tracing.Endpoint))
+		}
+	case "file":
+		tracing = append(tracing, slog.String("file", c.Tracing.File))
+	}
+	if c.Tracing.Exporter != "none" {
+		tracing = append(tracing,
+			slog.String("service_name", c.Tracing.ServiceName),
+			slog.Float64("sample_ratio", c.Tracing.SampleRatio))
+	}
+
+	weather := []slog.Attr{slog.String("provider", c.Weather.Provider)}
+	if c.Weather.BaseURL != "" {
+		weather = append(weather, slog.String("base_url", c.Weather.BaseURL))
+	}
+	weather = append(weather, slog.String("timeout", c.Weather.Timeout.String()))
+
+	return slog.GroupValue(
+		slog.Group("server",
+			slog.String("port", c.Server.Port),
+			slog.String("host", c.Server.Host),
+			slog.String("environment", c.Server.Environment),
+			slog.Bool("debug", c.Server.Debug),
+			slog.String("read_timeout", c.Server.ReadTimeout.String()),
+			slog.String("write_timeout", c.Server.WriteTimeout.String()),
+		),
+		slog.Group("log",
+			slog.String("level", c.Log.Level.String()),
+			slog.String("format", c.Log.Format),
+		),
+		slog.Group("api",
+			slog.Int("rate_limit", c.API.RateLimit),
+			slog.Bool("enable_cors", c.API.EnableCORS),
+			slog.Bool("enable_gzip", c.API.EnableGzip),
+			slog.Bool("api_key_set", c.API.Key != ""),
+			slog.Bool("moderation_enabled", c.API.ModerationKey != ""),
+		),
+		slog.Group("features",
+			slog.Bool("health_check", c.Features.EnableHealthCheck),
+			slog.Bool("metrics", c.Features.EnableMetrics),
+			slog.Bool("profiling", c.Features.EnableProfiling),
+		),
+		slog.Group("metrics",
+			slog.Any("latency_buckets", c.Metrics.Buckets),
+		),
+		slog.Attr{Key: "tracing", Value: slog.GroupValue(tracing...)},
+		slog.Attr{Key: "weather", Value: slog.GroupValue(weather...)},
+		slog.Group("stats",
+			slog.String("interval", c.Stats.Interval.String()),
+			slog.String("disk_path", c.Stats.DiskPath),
+			slog.String("retention", c.Stats.Retention.String()),
+		),
+		slog.Group("content",
+			slog.String("daily_timezone", c.Content.DailyTimezone),
+		),
+		slog.Group("clock",
+			slog.Any("zones", c.Clock.Zones),
+		),
+	)
 }
@@ -0,0 +1,272 @@
+// API explorer: renders the OpenAPI description named by the data-spec
+// attribute of #api-explorer and lets visitors send requests from the page.
+(function () {
+  'use strict';
+
+  const root = document.getElementById('api-explorer');
+  if (!root) return;
+
+  const specURL = root.dataset.spec;
+  const keyStorage = 'api-explorer-';
+  const methodOrder = ['get', 'post', 'put', 'patch', 'delete'];
+  const formats = [
+    ['application/json', 'JSON'],
+    ['application/xml', 'XML'],
+    ['application/yaml', 'YAML'],
+    ['text/csv', 'CSV'],
+    ['text/plain', 'Text'],
+    ['text/html', 'HTML fragment'],
+  ];
+
+  // el creates an element; strings become text nodes, so nothing from the
+  // spec or a response is ever parsed as HTML
+  function el(tag, attrs, ...children) {
+    const node = document.createElement(tag);
+    for (const [key, value] of Object.entries(attrs || {})) {
+      if (value === undefined || value === null || value === false) continue;
+      if (key === 'class') node.className = value;
+      else if (key.startsWith('on')) node.addEventListener(key.slice(2), value);
+      else node.setAttribute(key, value === true ? '' : value);
+    }
+    for (const child of children.flat()) {
+      if (child === undefined || child === null || child === false) continue;
+      node.append(child instanceof Node ? child : document.createTextNode(String(child)));
+    }
+    return node;
+  }
+
+  function resolve(spec, schema) {
+    while (schema && schema.$ref) {
+      schema = spec.components.schemas[schema.$ref.split('/').pop()];
+    }
+    return schema || {};
+  }
+
+  function refName(schema) {
+    return schema && schema.$ref ? schema.$ref.split('/').pop() : '';
+  }
+
+  // example builds a sample value for a schema
+  function example(spec, schema, depth) {
+    depth = depth || 0;
+    schema = resolve(spec, schema);
+    if (depth > 6) return null;
+    if (schema.enum) return schema.enum[0];
+
+    switch (schema.type) {
+      case 'object': {
+        if (schema.additionalProperties) {
+          return { key: example(spec, schema.additionalProperties, depth + 1) };
+        }
+        const out = {};
+        for (const [name, property] of Object.entries(schema.properties || {})) {
+          out[name] = example(spec, property, depth + 1);
+        }
+        return out;
+      }
+      case 'array':
+        return [example(spec, schema.items, depth + 1)];
+      case 'string':
+        return schema.format === 'date-time' ? new Date(0).toISOString() : 'string';
+      case 'integer':
+      case 'number':
+        return 0;
+      case 'boolean':
+        return false;
+    }
+    return null;
+  }
+
+  function paramInput(param) {
+    const schema = param.schema || {};
+    if (schema.enum || schema.type === 'boolean') {
+      const values = schema.enum || ['true', 'false'];
+      return el('select', {}, el('option', { value: '' }, ''), values.map((v) => el('option', { value: v }, v)));
+    }
+    const numeric = schema.type === 'integer' || schema.type === 'number';
+    return el('input', {
+      type: numeric ? 'number' : 'text',
+      step: schema.type === 'number' ? 'any' : undefined,
+      placeholder: schema.type || '',
+      required: param.required,
+    });
+  }
+
+  function operation(spec, entry, credentials) {
+    const { path, method, op } = entry;
+    const search = [method, path, op.summary || '', op.operationId].join(' ').toLowerCase();
+    const details = el('details', { class: 'apx-op apx-' + method, 'data-search': search });
+
+    details.append(el('summary', {},
+      el('span', { class: 'apx-method' }, method.toUpperCase()),
+      el('code', { class: 'apx-path' }, path),
+      el('span', { class: 'apx-summary' }, op.summary || ''),
+      op.deprecated ? el('span', { class: 'apx-deprecated' }, 'deprecated') : null));
+
+    const body = el('div', { class: 'apx-body' });
+    if (op.description) body.append(el('p', { class: 'apx-description' }, op.description));
+
+    const inputs = [];
+    if (op.parameters && op.parameters.length) {
+      const rows = op.parameters.map((param) => {
+        const input = paramInput(param);
+        inputs.push({ param, input });
+        return el('tr', {},
+          el('td', {}, el('code', {}, param.name), param.required ? el('span', { class: 'apx-required' }, ' *') : null),
+          el('td', {}, param.in),
+          el('td', {}, param.description || ''),
+          el('td', {}, input));
+      });
+      body.append(el('h3', {}, 'Parameters'), el('table', { class: 'apx-params' },
+        el('thead', {}, el('tr', {}, el('th', {}, 'Name'), el('th', {}, 'In'), el('th', {}, 'Description'), el('th', {}, 'Value'))),
+        el('tbody', {}, rows)));
+    }
+
+    let bodyInput = null;
+    if (op.requestBody) {
+      const schema = op.requestBody.content['application/json'].schema;
+      bodyInput = el('textarea', { rows: 6, class: 'apx-textarea', spellcheck: 'false' });
+      bodyInput.value = JSON.stringify(example(spec, schema), null, 2);
+      body.append(el('h3', {}, 'Request body ', el('code', { class: 'apx-schema' }, refName(schema))), bodyInput);
+    }
+
+    body.append(el('h3', {}, 'Responses'));
+    for (const [status, response] of Object.entries(op.responses)) {
+      const media = Object.values(response.content || {})[0];
+      const schema = media && media.schema;
+      const row = el('details', { class: 'apx-response' }, el('summary', {},
+        el('strong', {}, status), ' ', response.description,
+        schema ? el('code', { class: 'apx-schema' }, refName(schema) || schema.type || 'any') : null));
+      if (schema) row.append(el('pre', {}, JSON.stringify(example(spec, schema), null, 2)));
+      body.append(row);
+    }
+
+    const accept = el('select', {}, formats.map(([type, label]) => el('option', { value: type }, label)));
+    const output = el('div', { class: 'apx-output' });
+    const send = el('button', { type: 'button', class: 'apx-send' }, 'Send request');
+    body.append(el('div', { class: 'apx-try' }, el('label', {}, 'Accept ', accept), send), output);
+
+    send.addEventListener('click', async () => {
+      let url = path;
+      const query = new URLSearchParams();
+      for (const { param, input } of inputs) {
+        const value = input.value.trim();
+        if (param.in === 'path') {
+          if (!value) {
+            output.replaceChildren(el('p', { class: 'apx-error' }, 'Path parameter ' + param.name + ' is required'));
+            return;
+          }
+          url = url.replace('{' + param.name + '}', encodeURIComponent(value));
+        } else if (value !== '') {
+          query.append(param.name, value);
+        }
+      }
+      if (query.toString()) url += '?' + query;
+
+      const init = { method: method.toUpperCase(), headers: { Accept: accept.value } };
+      for (const { header, input } of credentials) {
+        if (input.value) init.headers[header] = input.value;
+      }
+      if (bodyInput) {
+        init.headers['Content-Type'] = 'application/json';
+        init.body = bodyInput.value;
+      }
+
+      send.disabled = true;
+      const started = performance.now();
+      try {
+        const res = await fetch(url, init);
+        const text = await res.text();
+        const elapsed = Math.round(performance.now() - started);
+
+        let pretty = text;
+        if ((res.headers.get('Content-Type') || '').includes('json')) {
+          try {
+            pretty = JSON.stringify(JSON.parse(text), null, 2);
+          } catch (_) {
+            // show the body as sent
+          }
+        }
+        const headers = [];
+        res.headers.forEach((value, name) => headers.push(name + ': ' + value));
+
+        output.replaceChildren(
+          el('p', { class: 'apx-status ' + (res.ok ? 'apx-ok' : 'apx-fail') },
+            res.status + ' ' + res.statusText + ' · ' + elapsed + ' ms'),
+          el('code', { class: 'apx-url' }, init.method + ' ' + url),
+          el('details', {}, el('summary', {}, 'Response headers'), el('pre', {}, headers.join('\n'))),
+          el('pre', {}, pretty || '(empty body)'));
+      } catch (err) {
+        output.replaceChildren(el('p', { class: 'apx-error' }, String(err)));
+      } finally {
+        send.disabled = false;
+      }
+    });
+
+    details.append(body);
+    return details;
+  }
+
+  function render(spec) {
+    // One input per header credential, remembered across visits
+    const credentials = [];
+    const schemes = (spec.components && spec.components.securitySchemes) || {};
+    for (const [name, scheme] of Object.entries(schemes)) {
+      if (scheme.type !== 'apiKey' || scheme.in !== 'header') continue;
+      const input = el('input', {
+        type: 'password',
+        placeholder: 'optional',
+        title: scheme.description,
+        value: localStorage.getItem(keyStorage + name) || '',
+      });
+      input.addEventListener('input', () => localStorage.setItem(keyStorage + name, input.value));
+      credentials.push({ header: scheme.name, input });
+    }
+
+    const filter = el('input', { type: 'search', placeholder: 'Filter operations', class: 'apx-filter' });
+
+    root.replaceChildren(
+      el('div', { class: 'apx-header' },
+        el('h1', {}, spec.info.title, ' ', el('span', { class: 'apx-version' }, spec.info.version),
+          ' ', el('span', { class: 'apx-version' }, 'OAS ' + spec.openapi)),
+        spec.info.description ? el('p', {}, spec.info.description) : null,
+        el('a', { href: specURL, class: 'apx-link' }, specURL)),
+      el('div', { class: 'apx-toolbar' }, filter,
+        credentials.map(({ header, input }) => el('label', {}, header + ' ', input))));
+
+    const groups = new Map((spec.tags || []).map((tag) => [tag.name, []]));
+    for (const [path, item] of Object.entries(spec.paths)) {
+      for (const [method, op] of Object.entries(item)) {
+        const tag = (op.tags || ['other'])[0];
+        if (!groups.has(tag)) groups.set(tag, []);
+        groups.get(tag).push({ path, method, op });
+      }
+    }
+
+    for (const [tag, entries] of groups) {
+      if (!entries.length) continue;
+      entries.sort((a, b) => a.path.localeCompare(b.path) || methodOrder.indexOf(a.method) - methodOrder.indexOf(b.method));
+      root.append(el('section', { class: 'apx-tag' }, el('h2', {}, tag), entries.map((entry) => operation(spec, entry, credentials))));
+    }
+
+    filter.addEventListener('input', () => {
+      const q = filter.value.trim().toLowerCase();
+      root.querySelectorAll('.apx-op').forEach((op) => {
+        op.hidden = q !== '' && !op.dataset.search.includes(q);
+      });
+      root.querySelectorAll('.apx-tag').forEach((section) => {
+        section.hidden = !section.querySelector('.apx-op:not([hidden])');
+      });
+    });
+  }
+
+  fetch(specURL)
+    .then((res) => {
+      if (!res.ok) throw new Error(res.status + ' ' + res.statusText);
+      return res.json();
+    })
+    .then(render)
+    .catch((err) => {
+      root.replaceChildren(el('p', { class: 'apx-error' }, 'Could not load the API description: ' + err.message));
+    });
+})();
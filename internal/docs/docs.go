@@ -0,0 +1,20 @@
+// Package docs embeds the scripts and styles of the API explorer, so the
+// explorer works without a CDN or the static directory.
+package docs
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Assets returns the explorer's assets, rooted at the assets directory
+func Assets() fs.FS {
+	sub, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
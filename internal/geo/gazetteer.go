@@ -0,0 +1,98 @@
+// Package geo provides offline geocoding backed by an embedded city gazetteer
+package geo
+
+import (
+	_ "embed"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+)
+
+//go:embed cities.json
+var citiesJSON []byte
+
+// earthRadiusKm is the mean radius of the earth in kilometres
+const earthRadiusKm = 6371.0
+
+// City represents a single gazetteer entry
+type City struct {
+	Name      string  `json:"name"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+// Label returns the display name of the city, e.g. "Amsterdam, NL"
+func (c City) Label() string {
+	return c.Name + ", " + c.Country
+}
+
+var (
+	cities      []City
+	citiesByKey map[string]City
+)
+
+func init() {
+	if err := json.Unmarshal(citiesJSON, &cities); err != nil {
+		panic("geo: invalid embedded gazetteer: " + err.Error())
+	}
+
+	citiesByKey = make(map[string]City, len(cities)*2)
+	for _, city := range cities {
+		citiesByKey[normalize(city.Name)] = city
+		citiesByKey[normalize(city.Label())] = city
+	}
+}
+
+// Cities returns all gazetteer entries sorted by name
+func Cities() []City {
+	result := make([]City, len(cities))
+	copy(result, cities)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Lookup finds a city by name. Both "Amsterdam" and "Amsterdam, NL" are
+// accepted, case-insensitively.
+func Lookup(name string) (City, bool) {
+	city, ok := citiesByKey[normalize(name)]
+	return city, ok
+}
+
+// Nearest returns the gazetteer city closest to the given coordinates and
+// its distance in kilometres
+func Nearest(lat, lon float64) (City, float64) {
+	var nearest City
+	best := math.Inf(1)
+	for _, city := range cities {
+		if d := Distance(lat, lon, city.Latitude, city.Longitude); d < best {
+			nearest, best = city, d
+		}
+	}
+	return nearest, best
+}
+
+// Distance returns the great-circle distance between two points in kilometres
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// normalize lowercases a name and collapses whitespace around commas
+func normalize(name string) string {
+	parts := strings.Split(strings.ToLower(name), ",")
+	for i, part := range parts {
+		parts[i] = strings.Join(strings.Fields(part), " ")
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// apiSpec is the encoded OpenAPI description, generated at startup
+var apiSpec []byte
+
+// SetAPISpec encodes the OpenAPI description served by GetAPISpec
+func SetAPISpec(doc interface{}) error {
+	spec, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	apiSpec = spec
+	return nil
+}
+
+// GetAPISpec returns the OpenAPI description of the API
+func GetAPISpec(c echo.Context) error {
+	if apiSpec == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "API description not available")
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, apiSpec)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateWindowSeconds is the longest window error rates are reported over
+const rateWindowSeconds = 15 * 60
+
+// ErrorRate summarizes the responses sent during a window. Errors are
+// responses with a 4xx or 5xx status; rates are fractions of Requests.
+type ErrorRate struct {
+	Requests        int64   `json:"requests"`
+	Errors          int64   `json:"errors"`
+	ServerErrors    int64   `json:"server_errors"`
+	ErrorRate       float64 `json:"error_rate"`
+	ServerErrorRate float64 `json:"server_error_rate"`
+}
+
+// ErrorRates are the error rates over sliding windows ending now
+type ErrorRates struct {
+	OneMinute      ErrorRate `json:"1m"`
+	FiveMinutes    ErrorRate `json:"5m"`
+	FifteenMinutes ErrorRate `json:"15m"`
+}
+
+// secondCounts are the responses sent during one second
+type secondCounts struct {
+	second       int64
+	requests     int64
+	errors       int64
+	serverErrors int64
+}
+
+// slidingCounts keeps per-second response counts for the longest window in
+// a ring indexed by Unix second
+type slidingCounts struct {
+	mu      sync.Mutex
+	seconds [rateWindowSeconds]secondCounts
+}
+
+// add records a response sent at now
+func (s *slidingCounts) add(now time.Time, status int) {
+	second := now.Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := &s.seconds[second%rateWindowSeconds]
+	if counts.second != second {
+		*counts = secondCounts{second: second}
+	}
+	counts.requests++
+	if status >= http.StatusBadRequest {
+		counts.errors++
+	}
+	if status >= http.StatusInternalServerError {
+		counts.serverErrors++
+	}
+}
+
+// rate sums the responses of the window ending at now
+func (s *slidingCounts) rate(now time.Time, window time.Duration) ErrorRate {
+	last := now.Unix()
+	first := last - int64(window/time.Second)
+
+	var rate ErrorRate
+	s.mu.Lock()
+	for _, counts := range s.seconds {
+		if counts.second > first && counts.second <= last {
+			rate.Requests += counts.requests
+			rate.Errors += counts.errors
+			rate.ServerErrors += counts.serverErrors
+		}
+	}
+	s.mu.Unlock()
+
+	if rate.Requests > 0 {
+		rate.ErrorRate = float64(rate.Errors) / float64(rate.Requests)
+		rate.ServerErrorRate = float64(rate.ServerErrors) / float64(rate.Requests)
+	}
+	return rate
+}
+
+// rates reports the 1, 5 and 15 minute windows ending at now
+func (s *slidingCounts) rates(now time.Time) ErrorRates {
+	return ErrorRates{
+		OneMinute:      s.rate(now, time.Minute),
+		FiveMinutes:    s.rate(now, 5*time.Minute),
+		FifteenMinutes: s.rate(now, 15*time.Minute),
+	}
+}
+
+// reset forgets every recorded response
+func (s *slidingCounts) reset() {
+	s.mu.Lock()
+	s.seconds = [rateWindowSeconds]secondCounts{}
+	s.mu.Unlock()
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Damianko135/playground-go/internal/logging"
+	"github.com/Damianko135/playground-go/internal/middleware"
+	"github.com/Damianko135/playground-go/internal/respond"
+	"github.com/Damianko135/playground-go/internal/utils"
+	"github.com/Damianko135/playground-go/views"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// ProblemContentType is the media type of problem details responses
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object, the body of every API
+// error. Errors lists the invalid fields of a validation failure.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// newProblem describes an error response to the request
+func newProblem(c echo.Context, status int, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request().URL.Path,
+		RequestID: requestID(c),
+	}
+}
+
+// requestID returns the ID assigned by the request ID middleware
+func requestID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
+
+// problemFor converts an error returned by a handler or middleware.
+// Unexpected errors are reported without their message unless the server
+// runs in debug mode.
+func problemFor(c echo.Context, err error) Problem {
+	var fields validationErrors
+	if errors.As(err, &fields) {
+		p := newProblem(c, http.StatusBadRequest, "One or more request parameters are invalid")
+		p.Errors = fields
+		return p
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		detail := fmt.Sprint(he.Message)
+		if detail == http.StatusText(he.Code) {
+			detail = ""
+		}
+		return newProblem(c, he.Code, detail)
+	}
+
+	var detail string
+	if c.Echo().Debug {
+		detail = err.Error()
+	}
+	return newProblem(c, http.StatusInternalServerError, detail)
+}
+
+// HTTPErrorHandler writes errors returned by handlers and middleware
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	if err := writeError(c, problemFor(c, err), err); err != nil {
+		logging.FromContext(c.Request().Context()).Error("writing error response failed", "error", err)
+	}
+}
+
+// writeError writes p as problem details. HTMX requests get an error
+// fragment instead, and browsers outside the API an error page. Cause is
+// the error p describes, if any.
+func writeError(c echo.Context, p Problem, cause error) error {
+	varyByFormat(c)
+
+	switch {
+	case c.Request().Method == http.MethodHead:
+		return c.NoContent(p.Status)
+	case isHTMX(c):
+		return renderError(c, p, fragments.ErrorMessage(p.Title, p.Detail, p.fieldMessages(), p.RequestID))
+	case wantsErrorPage(c):
+		return renderError(c, p, errorPage(c, p, cause))
+	}
+	return writeProblem(c, p)
+}
+
+// fieldMessages lists the validation errors as sentences
+func (p Problem) fieldMessages() []string {
+	messages := make([]string, len(p.Errors))
+	for i, fe := range p.Errors {
+		messages[i] = fe.Field + " " + fe.Message
+	}
+	return messages
+}
+
+// isHTMX reports whether the request comes from HTMX or an /htmx/* route
+func isHTMX(c echo.Context) bool {
+	return c.Request().Header.Get("HX-Request") == "true" || strings.HasPrefix(c.Request().URL.Path, "/htmx/")
+}
+
+// wantsErrorPage reports whether a browser asked for a page outside the API
+func wantsErrorPage(c echo.Context) bool {
+	path := c.Request().URL.Path
+	if path == "/api" || strings.HasPrefix(path, "/api/") {
+		return false
+	}
+	format, err := respond.Negotiate(c.Request(), true)
+	return err == nil && format == respond.HTML
+}
+
+// errorPage picks the page for a failed browser request. In debug mode
+// server errors show the developer page.
+func errorPage(c echo.Context, p Problem, cause error) templ.Component {
+	switch {
+	case p.Status >= http.StatusInternalServerError && c.Echo().Debug && cause != nil:
+		return views.DebugError(debugDetails(c, p, cause))
+	case p.Status >= http.StatusInternalServerError:
+		return views.ServerError(p.Status, p.RequestID)
+	case p.Status == http.StatusNotFound:
+		return views.NotFound(c.Request().URL.Path, p.RequestID)
+	}
+	return views.ErrorPage(p.Status, p.Title, p.Detail, p.RequestID)
+}
+
+// redactedHeaders are not shown on the debug page
+var redactedHeaders = map[string]bool{
+	"Authorization":   true,
+	"Cookie":          true,
+	"X-Api-Key":       true,
+	"X-Moderator-Key": true,
+}
+
+// debugDetails collects what the debug page shows about a failed request
+func debugDetails(c echo.Context, p Problem, cause error) views.DebugDetails {
+	d := views.DebugDetails{
+		Status:    p.Status,
+		Title:     p.Title,
+		Error:     cause.Error(),
+		Method:    c.Request().Method,
+		URL:       c.Request().URL.String(),
+		Route:     c.Path(),
+		RemoteIP:  c.RealIP(),
+		RequestID: p.RequestID,
+	}
+
+	for err := cause; err != nil; err = errors.Unwrap(err) {
+		d.Chain = append(d.Chain, fmt.Sprintf("%T: %v", err, err))
+	}
+
+	var panicked *middleware.PanicError
+	if errors.As(cause, &panicked) {
+		d.Stack = string(panicked.Stack)
+	}
+
+	names := make([]string, 0, len(c.Request().Header))
+	for name := range c.Request().Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(c.Request().Header.Values(name), ", ")
+		if redactedHeaders[name] {
+			value = "[redacted]"
+		}
+		d.Headers = append(d.Headers, views.DebugHeader{Name: name, Value: value})
+	}
+
+	return d
+}
+
+// writeProblem writes p as an application/problem+json response
+func writeProblem(c echo.Context, p Problem) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.Blob(p.Status, ProblemContentType, body)
+}
+
+// renderError writes an HTML error with the problem's status
+func renderError(c echo.Context, p Problem, component templ.Component) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(p.Status)
+	return utils.Render(c, component)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/logging"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// Forecast length limits for the forecast endpoints
+const (
+	defaultForecastDays = 5
+	maxForecastDays     = 14
+)
+
+// ForecastDay represents the forecast for a single day
+type ForecastDay struct {
+	Date                string  `json:"date"`
+	MinTemperature      float64 `json:"min_temperature"`
+	MaxTemperature      float64 `json:"max_temperature"`
+	PrecipitationChance int     `json:"precipitation_chance"`
+	Description         string  `json:"description"`
+	Icon                string  `json:"icon"`
+}
+
+// WeatherForecast is the response of the forecast endpoint
+type WeatherForecast struct {
+	Location  string        `json:"location"`
+	Latitude  float64       `json:"latitude"`
+	Longitude float64       `json:"longitude"`
+	Units     string        `json:"units"`
+	Days      []ForecastDay `json:"days"`
+}
+
+// TableKey makes the CSV form one row per day
+func (WeatherForecast) TableKey() string { return "days" }
+
+// Forecast returns a mock daily forecast. Today's entry is derived from the
+// same data as Current, so its range always contains the current temperature.
+func (p *StaticWeatherProvider) Forecast(ctx context.Context, query WeatherQuery, days int) ([]ForecastDay, error) {
+	today := time.Now()
+	forecast := make([]ForecastDay, 0, days)
+
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i)
+		day := mockWeather(query, date)
+
+		minTemp, maxTemp := day.Temperature-day.Spread, day.Temperature+day.Spread
+		if query.Units == UnitsImperial {
+			minTemp, maxTemp = celsiusToFahrenheit(minTemp), celsiusToFahrenheit(maxTemp)
+		}
+
+		forecast = append(forecast, ForecastDay{
+			Date:                date.Format("2006-01-02"),
+			MinTemperature:      roundTenth(minTemp),
+			MaxTemperature:      roundTenth(maxTemp),
+			PrecipitationChance: day.PrecipChance,
+			Description:         day.Condition.Description,
+			Icon:                day.Condition.Icon,
+		})
+	}
+
+	return forecast, nil
+}
+
+// Forecast fetches the daily forecast from the upstream service
+func (p *HTTPWeatherProvider) Forecast(ctx context.Context, query WeatherQuery, days int) ([]ForecastDay, error) {
+	params := upstreamParams(query)
+	params.Set("days", strconv.Itoa(days))
+
+	var forecast []ForecastDay
+	if err := p.get(ctx, "/forecast", params, &forecast); err != nil {
+		return nil, err
+	}
+
+	if len(forecast) > days {
+		forecast = forecast[:days]
+	}
+	return forecast, nil
+}
+
+// parseForecastQuery resolves the weather query plus the days parameter
+func parseForecastQuery(c echo.Context) (WeatherQuery, int, validationErrors) {
+	query, errs := parseWeatherQuery(c)
+
+	days := defaultForecastDays
+	if daysParam := c.QueryParam("days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed < 1 || parsed > maxForecastDays {
+			errs.add("days", fmt.Sprintf("must be a whole number between 1 and %d", maxForecastDays))
+		} else {
+			days = parsed
+		}
+	}
+
+	return query, days, errs
+}
+
+// weatherForecast loads the forecast for a resolved query
+func weatherForecast(c echo.Context, query WeatherQuery, days int) (WeatherForecast, error) {
+	forecast := WeatherForecast{
+		Location:  query.Location,
+		Latitude:  query.Latitude,
+		Longitude: query.Longitude,
+		Units:     query.Units,
+	}
+
+	daily, err := weatherProvider.Forecast(c.Request().Context(), query, days)
+	if err != nil {
+		logging.FromContext(c.Request().Context()).Error("weather provider failed", "error", err)
+		return forecast, echo.NewHTTPError(http.StatusBadGateway, "Weather forecast unavailable")
+	}
+	forecast.Days = daily
+
+	return forecast, nil
+}
+
+// GetWeatherForecast returns a multi-day forecast for the requested location
+func GetWeatherForecast(c echo.Context) error {
+	query, days, errs := parseForecastQuery(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	forecast, err := weatherForecast(c, query, days)
+	if err != nil {
+		return err
+	}
+
+	return render(c, http.StatusOK, forecast, forecastFragment(forecast))
+}
+
+// forecastFragment renders the forecast as HTML fragment for HTMX
+func forecastFragment(forecast WeatherForecast) templ.Component {
+	symbol := temperatureSymbol(forecast.Units)
+
+	rows := make([]fragments.ForecastDay, len(forecast.Days))
+	for i, day := range forecast.Days {
+		label := day.Date
+		if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+			label = date.Format("Mon 2 Jan")
+		}
+
+		rows[i] = fragments.ForecastDay{
+			Label:               label,
+			Description:         day.Description,
+			PrecipitationChance: day.PrecipitationChance,
+			Min:                 fmt.Sprintf("%.0f%s", day.MinTemperature, symbol),
+			Max:                 fmt.Sprintf("%.0f%s", day.MaxTemperature, symbol),
+		}
+	}
+
+	return fragments.Forecast(forecast.Location, rows)
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// processStart is when the server started, for uptime reports
+var processStart = time.Now()
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status    string            `json:"status"`
@@ -35,7 +38,7 @@ func HealthCheck(c echo.Context) error {
 		Status:    "healthy",
 		Timestamp: time.Now(),
 		Version:   "1.0.0",
-		Uptime:    time.Since(startTime).String(),
+		Uptime:    time.Since(processStart).String(),
 		System: SystemInfo{
 			GoVersion:    runtime.Version(),
 			NumGoroutine: runtime.NumGoroutine(),
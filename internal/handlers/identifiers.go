@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Damianko135/playground-go/internal/random"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+)
+
+// Kinds and limits for the identifier and password endpoints
+const (
+	kindPassword   = "password"
+	kindPassphrase = "passphrase"
+
+	maxIDCount           = 1000
+	maxNanoIDSize        = 128
+	maxPasswordCount     = 100
+	defaultPasswordLen   = 16
+	minPasswordLen       = 4
+	maxPasswordLen       = 128
+	defaultPassphraseLen = 5
+	maxPassphraseLen     = 16
+	maxSeparatorLen      = 3
+)
+
+// GeneratedIDs is the response of the identifier endpoint. ID is the first of
+// IDs.
+type GeneratedIDs struct {
+	Kind  string   `json:"kind"`
+	ID    string   `json:"id"`
+	IDs   []string `json:"ids"`
+	Count int      `json:"count"`
+}
+
+// TableKey makes the CSV form one row per identifier
+func (GeneratedIDs) TableKey() string { return "ids" }
+
+// GeneratedPasswords is the response of the password endpoint. Password is
+// the first of Passwords.
+type GeneratedPasswords struct {
+	Kind        string   `json:"kind"`
+	Password    string   `json:"password"`
+	Passwords   []string `json:"passwords"`
+	Count       int      `json:"count"`
+	EntropyBits float64  `json:"entropy_bits"`
+}
+
+// TableKey makes the CSV form one row per secret
+func (GeneratedPasswords) TableKey() string { return "passwords" }
+
+// parseCount reads an optional count parameter between 1 and max
+func parseCount(c echo.Context, max int, errs *validationErrors) int {
+	value := c.QueryParam("count")
+	if value == "" {
+		return 1
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 || count > max {
+		errs.add("count", fmt.Sprintf("must be a whole number between 1 and %d", max))
+		return 1
+	}
+	return count
+}
+
+// generateIDs creates the identifiers described by kind, count and size
+func generateIDs(c echo.Context) (GeneratedIDs, validationErrors) {
+	var errs validationErrors
+
+	kind := strings.ToLower(c.QueryParam("kind"))
+	if kind == "" {
+		kind = random.KindUUID4
+	}
+	if !random.ValidIDKind(kind) {
+		errs.add("kind", "must be one of "+strings.Join(random.IDKinds, ", "))
+	}
+	count := parseCount(c, maxIDCount, &errs)
+
+	size := random.DefaultNanoIDSize
+	if value := c.QueryParam("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		switch {
+		case kind != random.KindNanoID:
+			errs.add("size", "is only supported for nanoid")
+		case err != nil || parsed < 2 || parsed > maxNanoIDSize:
+			errs.add("size", fmt.Sprintf("must be a whole number between 2 and %d", maxNanoIDSize))
+		default:
+			size = parsed
+		}
+	}
+	if len(errs) > 0 {
+		return GeneratedIDs{}, errs
+	}
+
+	ids := make([]string, count)
+	for i := range ids {
+		if kind == random.KindNanoID {
+			ids[i] = random.NanoID(size)
+		} else {
+			ids[i] = random.ID(kind)
+		}
+	}
+	return GeneratedIDs{Kind: kind, ID: ids[0], IDs: ids, Count: count}, nil
+}
+
+// generatePasswords creates passwords or passphrases from the request
+// parameters. Classes may be repeated or comma separated.
+func generatePasswords(c echo.Context) (GeneratedPasswords, validationErrors) {
+	var errs validationErrors
+
+	kind := strings.ToLower(c.QueryParam("kind"))
+	if kind == "" {
+		kind = kindPassword
+	}
+	count := parseCount(c, maxPasswordCount, &errs)
+
+	result := GeneratedPasswords{Kind: kind, Count: count}
+	var generate func() string
+
+	switch kind {
+	case kindPassword:
+		length := defaultPasswordLen
+		if value := c.QueryParam("length"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < minPasswordLen || parsed > maxPasswordLen {
+				errs.add("length", fmt.Sprintf("must be a whole number between %d and %d", minPasswordLen, maxPasswordLen))
+			}
+			length = parsed
+		}
+
+		var classes []string
+		for _, value := range c.QueryParams()["classes"] {
+			for _, class := range strings.Split(value, ",") {
+				class = strings.ToLower(strings.TrimSpace(class))
+				switch {
+				case class == "" || containsString(classes, class):
+				case !random.ValidClass(class):
+					errs.add("classes", "must only contain "+strings.Join(random.Classes, ", "))
+				default:
+					classes = append(classes, class)
+				}
+			}
+		}
+		if len(classes) == 0 {
+			classes = random.Classes
+		}
+		if len(errs) > 0 {
+			return GeneratedPasswords{}, errs
+		}
+
+		generate = func() string {
+			password, entropy, _ := random.Password(length, classes)
+			result.EntropyBits = entropy
+			return password
+		}
+
+	case kindPassphrase:
+		words := defaultPassphraseLen
+		if value := c.QueryParam("words"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 2 || parsed > maxPassphraseLen {
+				errs.add("words", fmt.Sprintf("must be a whole number between 2 and %d", maxPassphraseLen))
+			}
+			words = parsed
+		}
+
+		separator := "-"
+		if values, ok := c.QueryParams()["separator"]; ok {
+			separator = values[0]
+			if len(separator) > maxSeparatorLen {
+				errs.add("separator", fmt.Sprintf("must be at most %d characters", maxSeparatorLen))
+			}
+		}
+
+		capitalize := false
+		if value := c.QueryParam("capitalize"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				errs.add("capitalize", "must be true or false")
+			}
+			capitalize = parsed
+		}
+		if len(errs) > 0 {
+			return GeneratedPasswords{}, errs
+		}
+
+		generate = func() string {
+			passphrase, entropy := random.Passphrase(words, separator, capitalize)
+			result.EntropyBits = entropy
+			return passphrase
+		}
+
+	default:
+		errs.add("kind", "must be password or passphrase")
+		return GeneratedPasswords{}, errs
+	}
+
+	result.Passwords = make([]string, count)
+	for i := range result.Passwords {
+		result.Passwords[i] = generate()
+	}
+	result.Password = result.Passwords[0]
+	result.EntropyBits = math.Round(result.EntropyBits*10) / 10
+	return result, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRandomID generates UUID v4, UUID v7, ULID or NanoID identifiers
+func GetRandomID(c echo.Context) error {
+	ids, errs := generateIDs(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	return render(c, http.StatusOK, ids, fragments.SecretList(ids.IDs, ids.Kind))
+}
+
+// GetRandomPassword generates passwords from character classes, or
+// passphrases from the embedded wordlist
+func GetRandomPassword(c echo.Context) error {
+	passwords, errs := generatePasswords(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	caption := fmt.Sprintf("%s • about %.0f bits of entropy", passwords.Kind, passwords.EntropyBits)
+	return render(c, http.StatusOK, passwords, fragments.SecretList(passwords.Passwords, caption))
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/utils"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed data/jokes.json
+var seedJokesJSON []byte
+
+// Limits for joke submissions
+const (
+	maxJokeLength     = 280
+	maxPendingJokes   = 500
+	defaultJokeType   = "general"
+	jokeStatusPending = "pending"
+)
+
+// jokeTypePattern restricts joke types to short lowercase slugs
+var jokeTypePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// Errors returned by JokeStore implementations
+var (
+	ErrJokeNotFound           = errors.New("joke not found")
+	ErrJokeQueueFull          = errors.New("joke submission queue is full")
+	ErrJokeSubmissionNotFound = errors.New("joke submission not found")
+)
+
+// JokeData represents a joke
+type JokeData struct {
+	ID        int    `json:"id"`
+	Setup     string `json:"setup"`
+	Punchline string `json:"punchline"`
+	Type      string `json:"type"`
+}
+
+// JokeSubmission is a user-submitted joke awaiting moderation
+type JokeSubmission struct {
+	JokeData
+	Status      string    `json:"status"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// JokeTypes is the response of the joke category endpoint
+type JokeTypes struct {
+	Types []string `json:"types"`
+}
+
+// TableKey makes the CSV form one row per category
+func (JokeTypes) TableKey() string { return "types" }
+
+// PendingJokes is the response of the moderation queue endpoint
+type PendingJokes struct {
+	Submissions []JokeSubmission `json:"submissions"`
+}
+
+// TableKey makes the CSV form one row per submission
+func (PendingJokes) TableKey() string { return "submissions" }
+
+// JokeStore is a repository of approved jokes plus a moderation queue
+type JokeStore interface {
+	// List returns the approved jokes of a type, or all of them if jokeType
+	// is empty, ordered by ID
+	List(jokeType string) []JokeData
+	// Get returns the approved joke with the given ID
+	Get(id int) (JokeData, error)
+	// Types returns the sorted types that have at least one approved joke
+	Types() []string
+	// Submit queues a joke for moderation
+	Submit(joke JokeData) (JokeSubmission, error)
+	// Pending returns the queued submissions, oldest first
+	Pending() []JokeSubmission
+	// Approve moves a submission into the approved jokes
+	Approve(id int) (JokeData, error)
+	// Reject discards a submission
+	Reject(id int) error
+}
+
+// jokeStore is the store used by the joke handlers
+var jokeStore JokeStore = mustSeedJokeStore()
+
+// SetJokeStore replaces the store used by the joke handlers
+func SetJokeStore(store JokeStore) {
+	jokeStore = store
+}
+
+// MemoryJokeStore is an in-memory JokeStore. Approved jokes and submissions
+// share one ID sequence, so an approved submission keeps its ID.
+type MemoryJokeStore struct {
+	mu      sync.RWMutex
+	jokes   map[int]JokeData
+	pending map[int]JokeSubmission
+	nextID  int
+}
+
+// NewMemoryJokeStore creates a store holding the given approved jokes
+func NewMemoryJokeStore(seed []JokeData) *MemoryJokeStore {
+	store := &MemoryJokeStore{
+		jokes:   make(map[int]JokeData),
+		pending: make(map[int]JokeSubmission),
+		nextID:  1,
+	}
+	for _, joke := range seed {
+		joke.ID = store.nextID
+		store.nextID++
+		store.jokes[joke.ID] = joke
+	}
+	return store
+}
+
+// mustSeedJokeStore creates a memory store seeded from the embedded jokes
+func mustSeedJokeStore() *MemoryJokeStore {
+	var seed []JokeData
+	if err := json.Unmarshal(seedJokesJSON, &seed); err != nil {
+		panic("handlers: invalid embedded jokes: " + err.Error())
+	}
+	return NewMemoryJokeStore(seed)
+}
+
+// List returns the approved jokes of a type
+func (s *MemoryJokeStore) List(jokeType string) []JokeData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jokes := make([]JokeData, 0, len(s.jokes))
+	for _, joke := range s.jokes {
+		if jokeType == "" || joke.Type == jokeType {
+			jokes = append(jokes, joke)
+		}
+	}
+	sort.Slice(jokes, func(i, j int) bool {
+		return jokes[i].ID < jokes[j].ID
+	})
+	return jokes
+}
+
+// Get returns the approved joke with the given ID
+func (s *MemoryJokeStore) Get(id int) (JokeData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	joke, ok := s.jokes[id]
+	if !ok {
+		return JokeData{}, ErrJokeNotFound
+	}
+	return joke, nil
+}
+
+// Types returns the types that have approved jokes
+func (s *MemoryJokeStore) Types() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	types := []string{}
+	for _, joke := range s.jokes {
+		if !seen[joke.Type] {
+			seen[joke.Type] = true
+			types = append(types, joke.Type)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Submit queues a joke for moderation
+func (s *MemoryJokeStore) Submit(joke JokeData) (JokeSubmission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) >= maxPendingJokes {
+		return JokeSubmission{}, ErrJokeQueueFull
+	}
+
+	joke.ID = s.nextID
+	s.nextID++
+	submission := JokeSubmission{
+		JokeData:    joke,
+		Status:      jokeStatusPending,
+		SubmittedAt: time.Now(),
+	}
+	s.pending[joke.ID] = submission
+	return submission, nil
+}
+
+// Pending returns the queued submissions
+func (s *MemoryJokeStore) Pending() []JokeSubmission {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending := make([]JokeSubmission, 0, len(s.pending))
+	for _, submission := range s.pending {
+		pending = append(pending, submission)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ID < pending[j].ID
+	})
+	return pending
+}
+
+// Approve moves a submission into the approved jokes
+func (s *MemoryJokeStore) Approve(id int) (JokeData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	submission, ok := s.pending[id]
+	if !ok {
+		return JokeData{}, ErrJokeSubmissionNotFound
+	}
+	delete(s.pending, id)
+	s.jokes[id] = submission.JokeData
+	return submission.JokeData, nil
+}
+
+// Reject discards a submission
+func (s *MemoryJokeStore) Reject(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[id]; !ok {
+		return ErrJokeSubmissionNotFound
+	}
+	delete(s.pending, id)
+	return nil
+}
+
+// jokeStoreError converts a store error into an HTTP error
+func jokeStoreError(err error) error {
+	switch {
+	case errors.Is(err, ErrJokeNotFound), errors.Is(err, ErrJokeSubmissionNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrJokeQueueFull):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	default:
+		return err
+	}
+}
+
+// jokeID parses the :id path parameter
+func jokeID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return 0, echo.NewHTTPError(http.StatusNotFound, ErrJokeNotFound.Error())
+	}
+	return id, nil
+}
+
+// selectJoke picks an approved joke, optionally restricted to the type
+// query parameter
+func selectJoke(c echo.Context) (JokeData, validationErrors, error) {
+	var errs validationErrors
+
+	jokeType := strings.ToLower(c.QueryParam("type"))
+	jokes := jokeStore.List(jokeType)
+	if len(jokes) == 0 {
+		if jokeType != "" {
+			errs.add("type", "must be one of: "+strings.Join(jokeStore.Types(), ", "))
+			return JokeData{}, errs, nil
+		}
+		return JokeData{}, nil, echo.NewHTTPError(http.StatusNotFound, "No jokes available")
+	}
+
+	index, errs := selectIndex(c, "joke:"+jokeType, len(jokes))
+	if len(errs) > 0 {
+		return JokeData{}, errs, nil
+	}
+	return jokes[index], nil, nil
+}
+
+// GetJoke returns a random joke. Use type to restrict the category.
+func GetJoke(c echo.Context) error {
+	joke, errs, err := selectJoke(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	if err != nil {
+		return err
+	}
+
+	return render(c, http.StatusOK, joke, fragments.JokeSetup(joke.ID, joke.Setup, joke.Type))
+}
+
+// GetJokeTypes returns the available joke categories
+func GetJokeTypes(c echo.Context) error {
+	return render(c, http.StatusOK, JokeTypes{Types: jokeStore.Types()}, nil)
+}
+
+// JokeRequest is the request body for joke submissions
+type JokeRequest struct {
+	Setup     string `json:"setup" form:"setup"`
+	Punchline string `json:"punchline" form:"punchline"`
+	Type      string `json:"type" form:"type"`
+}
+
+// bindJoke parses and validates a JSON or form-encoded joke submission
+func bindJoke(c echo.Context) (JokeData, validationErrors) {
+	var errs validationErrors
+	var req JokeRequest
+	if err := c.Bind(&req); err != nil {
+		errs.add("body", "must contain setup, punchline and type")
+		return JokeData{}, errs
+	}
+
+	joke := JokeData{
+		Setup:     strings.TrimSpace(req.Setup),
+		Punchline: strings.TrimSpace(req.Punchline),
+		Type:      strings.ToLower(strings.TrimSpace(req.Type)),
+	}
+	if joke.Type == "" {
+		joke.Type = defaultJokeType
+	}
+
+	checkJokeText(&errs, "setup", joke.Setup)
+	checkJokeText(&errs, "punchline", joke.Punchline)
+	if !jokeTypePattern.MatchString(joke.Type) {
+		errs.add("type", "must be a lowercase slug of at most 32 characters")
+	}
+
+	return joke, errs
+}
+
+// checkJokeText validates a required joke text field
+func checkJokeText(errs *validationErrors, field, value string) {
+	switch {
+	case value == "":
+		errs.add(field, "is required")
+	case len([]rune(value)) > maxJokeLength:
+		errs.add(field, fmt.Sprintf("must be at most %d characters", maxJokeLength))
+	}
+}
+
+// SubmitJoke queues a joke for moderation
+func SubmitJoke(c echo.Context) error {
+	joke, errs := bindJoke(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	submission, err := jokeStore.Submit(joke)
+	if err != nil {
+		return jokeStoreError(err)
+	}
+
+	return render(c, http.StatusAccepted, submission,
+		fragments.FormMessage("Thanks! Your joke is awaiting moderation.", true))
+}
+
+// ListPendingJokes returns the moderation queue
+func ListPendingJokes(c echo.Context) error {
+	return render(c, http.StatusOK, PendingJokes{Submissions: jokeStore.Pending()}, nil)
+}
+
+// ApproveJoke publishes a queued submission
+func ApproveJoke(c echo.Context) error {
+	id, err := jokeID(c)
+	if err != nil {
+		return err
+	}
+
+	joke, err := jokeStore.Approve(id)
+	if err != nil {
+		return jokeStoreError(err)
+	}
+
+	return render(c, http.StatusOK, joke, nil)
+}
+
+// RejectJoke discards a queued submission
+func RejectJoke(c echo.Context) error {
+	id, err := jokeID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := jokeStore.Reject(id); err != nil {
+		return jokeStoreError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetJokePunchlineHTML returns a joke's punchline as HTML fragment for HTMX
+func GetJokePunchlineHTML(c echo.Context) error {
+	id, err := jokeID(c)
+	if err != nil {
+		return err
+	}
+
+	joke, err := jokeStore.Get(id)
+	if err != nil {
+		return jokeStoreError(err)
+	}
+
+	return utils.Temple(fragments.JokePunchline(joke.Punchline))(c)
+}
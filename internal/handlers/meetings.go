@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// Defaults and limits for the meeting planner
+const (
+	defaultWorkStart       = "09:00"
+	defaultWorkEnd         = "17:00"
+	defaultPlannerDays     = 5
+	maxPlannerDays         = 31
+	maxPlannerGridDays     = 7
+	maxParticipants        = 12
+	defaultMeetingStep     = 30 * time.Minute
+	defaultMeetingDuration = 30 * time.Minute
+)
+
+// Participant is one attendee of a meeting: a time zone and working hours
+// in that zone. Working hours may wrap past midnight, such as 22:00-06:00.
+type Participant struct {
+	Zone      string `json:"zone"`
+	Label     string `json:"label"`
+	WorkStart string `json:"work_start"`
+	WorkEnd   string `json:"work_end"`
+
+	loc   *time.Location
+	start int // minutes after local midnight
+	end   int
+}
+
+// SlotTime is the start and end of a meeting slot in one participant's zone
+type SlotTime struct {
+	Zone      string `json:"zone"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	UTCOffset string `json:"utc_offset"`
+	DST       bool   `json:"dst"`
+}
+
+// MeetingSlot is a window in which every participant is working
+type MeetingSlot struct {
+	Start           string     `json:"start"`
+	End             string     `json:"end"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Local           []SlotTime `json:"local"`
+}
+
+// MeetingPlan is the response of the overlap endpoint
+type MeetingPlan struct {
+	Participants    []Participant `json:"participants"`
+	Timezone        string        `json:"timezone"`
+	From            string        `json:"from"`
+	To              string        `json:"to"`
+	Weekends        bool          `json:"weekends"`
+	StepMinutes     int           `json:"step_minutes"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Slots           []MeetingSlot `json:"slots"`
+}
+
+// TableKey makes the CSV form one row per slot
+func (MeetingPlan) TableKey() string { return "slots" }
+
+// meetingQuery holds the parsed parameters of a meeting planner request
+type meetingQuery struct {
+	participants []Participant
+	ref          *time.Location
+	from, to     time.Time // local midnight of the first and last day in ref
+	weekends     bool
+	step         time.Duration
+	duration     time.Duration
+}
+
+// working reports whether the participant is within working hours for the
+// whole of [t, t+d). Outside weekends=true, Saturday and Sunday in the
+// participant's zone are not working days.
+func (p Participant) working(t time.Time, d time.Duration, weekends bool) bool {
+	for _, instant := range []time.Time{t, t.Add(d - time.Minute)} {
+		local := instant.In(p.loc)
+		if !weekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+			return false
+		}
+		m := local.Hour()*60 + local.Minute()
+		if p.start < p.end && (m < p.start || m >= p.end) ||
+			p.start > p.end && m < p.start && m >= p.end {
+			return false
+		}
+	}
+	return true
+}
+
+// hoursIn formats the participant's working hours on the given day in loc
+func (p Participant) hoursIn(day time.Time, loc *time.Location) string {
+	local := day.In(p.loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, p.start, 0, 0, p.loc)
+	length := p.end - p.start
+	if length <= 0 {
+		length += 24 * 60
+	}
+	end := start.Add(time.Duration(length) * time.Minute)
+	return start.In(loc).Format("15:04") + "-" + end.In(loc).Format("15:04 MST")
+}
+
+// parseClock parses an HH:MM time into minutes after midnight
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// parseParticipants reads a comma-separated list of Zone or
+// Zone@HH:MM-HH:MM entries
+func parseParticipants(value string, errs *validationErrors) []Participant {
+	var participants []Participant
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		zone, hours, hasHours := strings.Cut(entry, "@")
+		p := Participant{Zone: strings.TrimSpace(zone), WorkStart: defaultWorkStart, WorkEnd: defaultWorkEnd}
+		if hasHours {
+			start, end, ok := strings.Cut(hours, "-")
+			if !ok {
+				errs.add("participants", fmt.Sprintf("%q: working hours must look like 09:00-17:00", entry))
+				continue
+			}
+			p.WorkStart, p.WorkEnd = strings.TrimSpace(start), strings.TrimSpace(end)
+		}
+
+		loc, err := loadZone(p.Zone)
+		if err != nil {
+			errs.add("participants", err.Error())
+			continue
+		}
+		startMin, okStart := parseClock(p.WorkStart)
+		endMin, okEnd := parseClock(p.WorkEnd)
+		if !okStart || !okEnd || startMin == endMin {
+			errs.add("participants", fmt.Sprintf("%q: working hours must look like 09:00-17:00", entry))
+			continue
+		}
+
+		p.Label, p.loc, p.start, p.end = zoneLabel(p.Zone), loc, startMin, endMin
+		participants = append(participants, p)
+	}
+
+	if len(participants) == 0 && len(*errs) == 0 || len(participants) > maxParticipants {
+		errs.add("participants", fmt.Sprintf("must list between 1 and %d participants", maxParticipants))
+	}
+	return participants
+}
+
+// parseMeetingQuery reads and validates the meeting planner parameters
+func parseMeetingQuery(c echo.Context) (meetingQuery, validationErrors) {
+	var errs validationErrors
+	q := meetingQuery{step: defaultMeetingStep, duration: defaultMeetingDuration}
+
+	q.participants = parseParticipants(c.QueryParam("participants"), &errs)
+	if len(errs) > 0 {
+		return q, errs
+	}
+
+	q.ref = q.participants[0].loc
+	if value := c.QueryParam("tz"); value != "" {
+		loc, err := loadZone(value)
+		if err != nil {
+			errs.add("tz", err.Error())
+			return q, errs
+		}
+		q.ref = loc
+	}
+
+	today := time.Now().In(q.ref)
+	q.from = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, q.ref)
+	if value := c.QueryParam("from"); value != "" {
+		from, err := time.ParseInLocation("2006-01-02", value, q.ref)
+		if err != nil {
+			errs.add("from", "must be a date like 2006-01-02")
+		}
+		q.from = from
+	}
+	q.to = q.from.AddDate(0, 0, defaultPlannerDays-1)
+	if value := c.QueryParam("to"); value != "" {
+		to, err := time.ParseInLocation("2006-01-02", value, q.ref)
+		if err != nil {
+			errs.add("to", "must be a date like 2006-01-02")
+		}
+		q.to = to
+	}
+	if len(errs) == 0 {
+		if q.to.Before(q.from) {
+			errs.add("to", "must not be before from")
+		} else if q.to.After(q.from.AddDate(0, 0, maxPlannerDays-1)) {
+			errs.add("to", fmt.Sprintf("range must cover at most %d days", maxPlannerDays))
+		}
+	}
+
+	if value := c.QueryParam("weekends"); value != "" {
+		weekends, err := strconv.ParseBool(value)
+		if err != nil {
+			errs.add("weekends", "must be true or false")
+		}
+		q.weekends = weekends
+	}
+
+	if value := c.QueryParam("step"); value != "" {
+		step, err := time.ParseDuration(value)
+		if err != nil || (step != 15*time.Minute && step != 30*time.Minute && step != time.Hour) {
+			errs.add("step", "must be 15m, 30m or 1h")
+		}
+		q.step = step
+	}
+	if value := c.QueryParam("duration"); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < time.Minute || duration > 24*time.Hour {
+			errs.add("duration", "must be a duration between 1m and 24h, like 45m")
+		}
+		q.duration = duration
+	}
+
+	return q, errs
+}
+
+// allWorking reports whether every participant works during [t, t+d)
+func (q meetingQuery) allWorking(t time.Time, d time.Duration) bool {
+	for _, p := range q.participants {
+		if !p.working(t, d, q.weekends) {
+			return false
+		}
+	}
+	return true
+}
+
+// end returns the instant after the last day of the range
+func (q meetingQuery) end() time.Time {
+	return q.to.AddDate(0, 0, 1)
+}
+
+// days returns local midnight in the reference zone of every day in range
+func (q meetingQuery) days() []time.Time {
+	var days []time.Time
+	for day := q.from; !day.After(q.to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// findSlots walks the range in steps and merges consecutive steps in which
+// everyone works into slots of at least the requested duration. Stepping
+// over UTC instants and converting each to local time makes DST changes in
+// any zone show up naturally.
+func (q meetingQuery) findSlots() []MeetingSlot {
+	slots := []MeetingSlot{}
+	var open time.Time
+
+	closeSlot := func(end time.Time) {
+		if !open.IsZero() && end.Sub(open) >= q.duration {
+			slots = append(slots, q.slot(open, end))
+		}
+		open = time.Time{}
+	}
+
+	end := q.end()
+	for t := q.from; t.Before(end); t = t.Add(q.step) {
+		if q.allWorking(t, q.step) {
+			if open.IsZero() {
+				open = t
+			}
+		} else {
+			closeSlot(t)
+		}
+	}
+	closeSlot(end)
+
+	return slots
+}
+
+// slot describes [start, end) for every participant
+func (q meetingQuery) slot(start, end time.Time) MeetingSlot {
+	slot := MeetingSlot{
+		Start:           start.UTC().Format(time.RFC3339),
+		End:             end.UTC().Format(time.RFC3339),
+		DurationMinutes: int(end.Sub(start).Minutes()),
+		Local:           make([]SlotTime, len(q.participants)),
+	}
+	for i, p := range q.participants {
+		localStart := start.In(p.loc)
+		slot.Local[i] = SlotTime{
+			Zone:      p.Zone,
+			Start:     localStart.Format("2006-01-02 15:04"),
+			End:       end.In(p.loc).Format("2006-01-02 15:04"),
+			UTCOffset: localStart.Format("-07:00"),
+			DST:       localStart.IsDST(),
+		}
+	}
+	return slot
+}
+
+// plan builds the response for the query
+func (q meetingQuery) plan() MeetingPlan {
+	return MeetingPlan{
+		Participants:    q.participants,
+		Timezone:        q.ref.String(),
+		From:            q.from.Format("2006-01-02"),
+		To:              q.to.Format("2006-01-02"),
+		Weekends:        q.weekends,
+		StepMinutes:     int(q.step.Minutes()),
+		DurationMinutes: int(q.duration.Minutes()),
+		Slots:           q.findSlots(),
+	}
+}
+
+// GetMeetingOverlap finds windows in which every participant is within
+// working hours. Participants are listed as Zone@HH:MM-HH:MM, separated by
+// commas; dates are read in tz, which defaults to the first participant's.
+func GetMeetingOverlap(c echo.Context) error {
+	q, errs := parseMeetingQuery(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	plan := q.plan()
+	return render(c, http.StatusOK, plan, q.grid(plan.Slots))
+}
+
+// grid renders an hour-by-hour grid per day as HTML fragment for HTMX.
+// Columns are hours in the reference zone, so days with a DST change have 23
+// or 25 columns.
+func (q meetingQuery) grid(slots []MeetingSlot) templ.Component {
+	days := q.days()
+	truncated := len(days) > maxPlannerGridDays
+	if truncated {
+		days = days[:maxPlannerGridDays]
+	}
+
+	grid := make([]fragments.MeetingDay, len(days))
+	for i, day := range days {
+		next := day.AddDate(0, 0, 1)
+		grid[i] = fragments.MeetingDay{Title: day.Format("Monday 2 January")}
+
+		for _, p := range q.participants {
+			row := fragments.MeetingRow{Label: p.Label}
+			for t := day; t.Before(next); t = t.Add(time.Hour) {
+				local := t.In(p.loc)
+				row.Cells = append(row.Cells, fragments.MeetingCell{
+					Working: p.working(t, time.Hour, q.weekends),
+					Title:   local.Format("Mon 15:04 MST"),
+					Text:    local.Format("15"),
+				})
+			}
+			grid[i].Rows = append(grid[i].Rows, row)
+		}
+
+		for t := day; t.Before(next); t = t.Add(time.Hour) {
+			grid[i].Everyone = append(grid[i].Everyone, fragments.MeetingCell{
+				Working: q.allWorking(t, time.Hour),
+				Title:   t.UTC().Format("15:04 UTC"),
+			})
+		}
+	}
+
+	summary := fragments.MeetingSummary{DurationMinutes: int(q.duration.Minutes()), Timezone: q.ref.String()}
+	if len(slots) > 0 {
+		summary.Slots = len(slots)
+		summary.FirstStart = slots[0].Start
+	} else {
+		for _, p := range q.participants {
+			summary.Hours = append(summary.Hours, fragments.MeetingHours{
+				Label:     p.Label,
+				Local:     p.WorkStart + "-" + p.WorkEnd,
+				Reference: p.hoursIn(q.from, q.ref),
+			})
+		}
+	}
+	if truncated {
+		summary.Truncated = maxPlannerGridDays
+	}
+
+	return fragments.MeetingPlanner(grid, summary)
+}
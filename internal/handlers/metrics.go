@@ -1,76 +1,193 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"runtime"
 	"sync/atomic"
 	"time"
 
+	"github.com/Damianko135/playground-go/internal/middleware"
 	"github.com/labstack/echo/v4"
 )
 
-// Metrics holds application metrics
+// Metrics holds application metrics. ErrorCount counts responses with a
+// 4xx or 5xx status, however the handler produced them.
 type Metrics struct {
-	RequestCount    int64     `json:"request_count"`
-	ErrorCount      int64     `json:"error_count"`
-	StartTime       time.Time `json:"start_time"`
-	LastRequestTime time.Time `json:"last_request_time"`
-	Uptime          string    `json:"uptime"`
-	GoRoutines      int       `json:"goroutines"`
-	MemoryUsage     uint64    `json:"memory_usage_mb"`
-	CPUCount        int       `json:"cpu_count"`
+	RequestCount    int64         `json:"request_count"`
+	ErrorCount      int64         `json:"error_count"`
+	StatusClasses   StatusClasses `json:"status_classes"`
+	Timeouts        int64         `json:"timeouts"`
+	Panics          int64         `json:"panics"`
+	ErrorRates      ErrorRates    `json:"error_rates"`
+	StartTime       time.Time     `json:"start_time"`
+	LastRequestTime time.Time     `json:"last_request_time"`
+	Uptime          string        `json:"uptime"`
+	GoRoutines      int           `json:"goroutines"`
+	MemoryUsage     uint64        `json:"memory_usage_mb"`
+	CPUCount        int           `json:"cpu_count"`
 }
 
-var (
-	requestCount    int64
-	errorCount      int64
-	lastRequestTime time.Time
-	startTime       = time.Now()
-)
+// StatusClasses counts responses by status class
+type StatusClasses struct {
+	Informational int64 `json:"1xx"`
+	Success       int64 `json:"2xx"`
+	Redirection   int64 `json:"3xx"`
+	ClientError   int64 `json:"4xx"`
+	ServerError   int64 `json:"5xx"`
+}
+
+// MetricsCollector counts the requests served by a server. Collectors are
+// independent of each other and safe for concurrent use, so a server or a
+// test can own one without sharing state.
+type MetricsCollector struct {
+	buckets []float64
+
+	requestCount atomic.Int64
+	errorCount   atomic.Int64
+	// statusClasses is indexed by the first digit of the status
+	statusClasses [6]atomic.Int64
+	timeouts      atomic.Int64
+	panics        atomic.Int64
+	window        slidingCounts
+	// Times are stored as Unix nanoseconds; zero means never
+	startTime       atomic.Int64
+	lastRequestTime atomic.Int64
+	prom            atomic.Pointer[httpMetrics]
+}
+
+// NewMetricsCollector creates a collector whose latency histogram uses the
+// given buckets, in seconds
+func NewMetricsCollector(buckets []float64) *MetricsCollector {
+	m := &MetricsCollector{buckets: buckets}
+	m.Reset()
+	return m
+}
+
+// Reset discards everything collected so far and restarts the clock
+func (m *MetricsCollector) Reset() {
+	m.requestCount.Store(0)
+	m.errorCount.Store(0)
+	for i := range m.statusClasses {
+		m.statusClasses[i].Store(0)
+	}
+	m.timeouts.Store(0)
+	m.panics.Store(0)
+	m.window.reset()
+	m.startTime.Store(time.Now().UnixNano())
+	m.lastRequestTime.Store(0)
+	m.prom.Store(newHTTPMetrics(m.buckets))
+}
 
 // IncrementRequestCount increments the request counter
-func IncrementRequestCount() {
-	atomic.AddInt64(&requestCount, 1)
-	lastRequestTime = time.Now()
+func (m *MetricsCollector) IncrementRequestCount() {
+	m.requestCount.Add(1)
+	m.lastRequestTime.Store(time.Now().UnixNano())
 }
 
-// IncrementErrorCount increments the error counter
-func IncrementErrorCount() {
-	atomic.AddInt64(&errorCount, 1)
+// RecordResponse classifies a finished request by the status sent and the
+// error the handler chain returned, if any
+func (m *MetricsCollector) RecordResponse(status int, err error) {
+	if class := status / 100; class >= 1 && class < len(m.statusClasses) {
+		m.statusClasses[class].Add(1)
+	}
+	if status >= http.StatusBadRequest {
+		m.errorCount.Add(1)
+	}
+
+	var panicked *middleware.PanicError
+	if errors.As(err, &panicked) {
+		m.panics.Add(1)
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout {
+		m.timeouts.Add(1)
+	}
+
+	m.window.add(time.Now(), status)
 }
 
-// GetMetrics returns application metrics
-func GetMetrics(c echo.Context) error {
-	var m runtime.MemStats
-	runtime.ReadMemStats(&m)
-
-	metrics := Metrics{
-		RequestCount:    atomic.LoadInt64(&requestCount),
-		ErrorCount:      atomic.LoadInt64(&errorCount),
-		StartTime:       startTime,
-		LastRequestTime: lastRequestTime,
-		Uptime:          time.Since(startTime).String(),
+// Snapshot returns the current metrics
+func (m *MetricsCollector) Snapshot() Metrics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	start := unixNanoTime(m.startTime.Load())
+	return Metrics{
+		RequestCount: m.requestCount.Load(),
+		ErrorCount:   m.errorCount.Load(),
+		StatusClasses: StatusClasses{
+			Informational: m.statusClasses[1].Load(),
+			Success:       m.statusClasses[2].Load(),
+			Redirection:   m.statusClasses[3].Load(),
+			ClientError:   m.statusClasses[4].Load(),
+			ServerError:   m.statusClasses[5].Load(),
+		},
+		Timeouts:        m.timeouts.Load(),
+		Panics:          m.panics.Load(),
+		ErrorRates:      m.window.rates(time.Now()),
+		StartTime:       start,
+		LastRequestTime: unixNanoTime(m.lastRequestTime.Load()),
+		Uptime:          time.Since(start).String(),
 		GoRoutines:      runtime.NumGoroutine(),
-		MemoryUsage:     bToMb(m.Alloc),
+		MemoryUsage:     bToMb(mem.Alloc),
 		CPUCount:        runtime.NumCPU(),
 	}
+}
+
+// unixNanoTime converts a stored time, keeping zero as the zero time
+func unixNanoTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// GetMetrics returns a handler serving the collector's metrics as JSON, or
+// in the Prometheus exposition format for scrapers
+func GetMetrics(m *MetricsCollector) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Add(echo.HeaderVary, "Accept")
 
-	return c.JSON(http.StatusOK, metrics)
+		promFormat, errs := wantsPrometheus(c)
+		if len(errs) > 0 {
+			return validationError(c, errs)
+		}
+		if promFormat {
+			m.prom.Load().handler.ServeHTTP(c.Response(), c.Request())
+			return nil
+		}
+
+		return c.JSON(http.StatusOK, m.Snapshot())
+	}
 }
 
-// MetricsMiddleware tracks request metrics
-func MetricsMiddleware() echo.MiddlewareFunc {
+// MetricsMiddleware records every request in the collector. Register it
+// before the recover middleware, so recovered panics reach it as errors.
+func MetricsMiddleware(m *MetricsCollector) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			IncrementRequestCount()
+			m.IncrementRequestCount()
+			done := m.prom.Load().track(c)
 
 			err := next(c)
 
-			if err != nil {
-				IncrementErrorCount()
-			}
+			status := responseStatus(c, err)
+			done(status)
+			m.RecordResponse(status, err)
 
 			return err
 		}
 	}
 }
+
+// responseStatus returns the status of a finished request. Errors are
+// written later by the error handler, so for those it is the status the
+// handler will send.
+func responseStatus(c echo.Context, err error) int {
+	if err != nil && !c.Response().Committed {
+		return problemFor(c, err).Status
+	}
+	return c.Response().Status
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Damianko135/playground-go/internal/config"
+	"github.com/labstack/echo/v4"
+)
+
+// newMetricsServer serves /ok, /missing and /fail through a collector
+func newMetricsServer(m *MetricsCollector) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Use(MetricsMiddleware(m))
+	e.GET("/ok", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "missing")
+	})
+	e.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError, "fail")
+	})
+	return e
+}
+
+// serveConcurrently sends each path n times from parallel goroutines
+func serveConcurrently(e *echo.Echo, n int, paths ...string) {
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+func TestMetricsCollectorsAreIndependent(t *testing.T) {
+	tests := []struct {
+		name    string
+		ok      int
+		missing int
+		fail    int
+	}{
+		{name: "mostly successful", ok: 40, missing: 5, fail: 1},
+		{name: "mostly failing", ok: 3, missing: 10, fail: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMetricsCollector(config.DefaultMetricsBuckets)
+			e := newMetricsServer(m)
+			serveConcurrently(e, tt.ok, "/ok")
+			serveConcurrently(e, tt.missing, "/missing")
+			serveConcurrently(e, tt.fail, "/fail")
+
+			got := m.Snapshot()
+			if want := int64(tt.ok + tt.missing + tt.fail); got.RequestCount != want {
+				t.Errorf("RequestCount = %d, want %d", got.RequestCount, want)
+			}
+			if want := int64(tt.missing + tt.fail); got.ErrorCount != want {
+				t.Errorf("ErrorCount = %d, want %d", got.ErrorCount, want)
+			}
+			wantClasses := StatusClasses{
+				Success:     int64(tt.ok),
+				ClientError: int64(tt.missing),
+				ServerError: int64(tt.fail),
+			}
+			if got.StatusClasses != wantClasses {
+				t.Errorf("StatusClasses = %+v, want %+v", got.StatusClasses, wantClasses)
+			}
+
+			m.Reset()
+			got = m.Snapshot()
+			if got.RequestCount != 0 || got.ErrorCount != 0 || got.StatusClasses != (StatusClasses{}) {
+				t.Errorf("after Reset: RequestCount = %d, ErrorCount = %d, StatusClasses = %+v, want zero",
+					got.RequestCount, got.ErrorCount, got.StatusClasses)
+			}
+			if !got.LastRequestTime.IsZero() {
+				t.Errorf("after Reset: LastRequestTime = %v, want zero", got.LastRequestTime)
+			}
+			if got.ErrorRates.OneMinute.Requests != 0 {
+				t.Errorf("after Reset: one-minute window has %d requests, want 0", got.ErrorRates.OneMinute.Requests)
+			}
+
+			// The collector keeps counting after a reset
+			serveConcurrently(e, 2, "/ok", "/missing")
+			got = m.Snapshot()
+			if got.RequestCount != 4 || got.StatusClasses.Success != 2 || got.StatusClasses.ClientError != 2 {
+				t.Errorf("after Reset and 4 requests: RequestCount = %d, StatusClasses = %+v",
+					got.RequestCount, got.StatusClasses)
+			}
+		})
+	}
+}
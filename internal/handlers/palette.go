@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Damianko135/playground-go/internal/palette"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+)
+
+// Limits for generated palettes
+const (
+	defaultPaletteSize = 5
+	maxPaletteSize     = 12
+	defaultHarmonyMode = palette.Analogous
+	maxExportColors    = 64
+	maxPaletteName     = 64
+)
+
+// GeneratedPalette is a palette derived from a base color and harmony mode
+type GeneratedPalette struct {
+	Base   string          `json:"base"`
+	Mode   string          `json:"mode"`
+	Colors []palette.Color `json:"colors"`
+}
+
+// paletteQuery holds the parsed parameters of a palette generation request
+type paletteQuery struct {
+	Base  palette.RGB
+	Mode  string
+	Count int
+}
+
+// parsePaletteQuery reads base, mode and count from the request. Without a
+// base color a random, moderately saturated one is used.
+func parsePaletteQuery(c echo.Context) (paletteQuery, validationErrors) {
+	var errs validationErrors
+	query := paletteQuery{Mode: defaultHarmonyMode, Count: defaultPaletteSize}
+
+	if value := c.QueryParam("base"); value != "" {
+		base, err := palette.ParseHex(value)
+		if err != nil {
+			errs.add("base", err.Error())
+		}
+		query.Base = base
+	} else {
+		query.Base = palette.HSL{H: rand.Float64() * 360, S: 65, L: 50}.RGB()
+	}
+
+	if value := c.QueryParam("mode"); value != "" {
+		mode := strings.ToLower(value)
+		if !palette.ValidMode(mode) {
+			errs.add("mode", "must be one of "+strings.Join(palette.Modes, ", "))
+		}
+		query.Mode = mode
+	}
+
+	if value := c.QueryParam("count"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 2 || count > maxPaletteSize {
+			errs.add("count", fmt.Sprintf("must be a whole number between 2 and %d", maxPaletteSize))
+		}
+		query.Count = count
+	}
+
+	return query, errs
+}
+
+// generatePalette builds the palette described by the request
+func generatePalette(c echo.Context) (GeneratedPalette, validationErrors, error) {
+	query, errs := parsePaletteQuery(c)
+	if len(errs) > 0 {
+		return GeneratedPalette{}, errs, nil
+	}
+
+	colors, err := palette.Generate(query.Base, query.Mode, query.Count)
+	if err != nil {
+		return GeneratedPalette{}, nil, err
+	}
+
+	return GeneratedPalette{
+		Base:   query.Base.Hex(),
+		Mode:   query.Mode,
+		Colors: colors,
+	}, nil, nil
+}
+
+// GeneratePalette returns a palette generated from a base color and harmony
+// mode, with WCAG contrast ratios for each color
+func GeneratePalette(c echo.Context) error {
+	generated, errs, err := generatePalette(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	if err != nil {
+		return err
+	}
+
+	return render(c, http.StatusOK, generated, fragments.GeneratedPalette(generated.Mode, generated.Base, generated.Colors))
+}
+
+// exportPaletteColors returns the name and colors of the palette to export:
+// an explicit colors list, a predefined palette by preset name, or a
+// generated palette
+func exportPaletteColors(c echo.Context) (string, []palette.RGB, validationErrors, error) {
+	var errs validationErrors
+
+	if value := c.QueryParam("colors"); value != "" {
+		parts := strings.Split(value, ",")
+		if len(parts) > maxExportColors {
+			errs.add("colors", fmt.Sprintf("must list at most %d colors", maxExportColors))
+			return "", nil, errs, nil
+		}
+		colors := make([]palette.RGB, 0, len(parts))
+		for _, part := range parts {
+			color, err := palette.ParseHex(part)
+			if err != nil {
+				errs.add("colors", fmt.Sprintf("%q: %s", part, err))
+				continue
+			}
+			colors = append(colors, color)
+		}
+		return "Custom Palette", colors, errs, nil
+	}
+
+	if value := c.QueryParam("preset"); value != "" {
+		for _, preset := range colorPalettes {
+			if strings.EqualFold(preset.Name, value) || palette.Slug(preset.Name) == value {
+				colors := make([]palette.RGB, len(preset.Colors))
+				for i, hex := range preset.Colors {
+					colors[i], _ = palette.ParseHex(hex)
+				}
+				return preset.Name, colors, nil, nil
+			}
+		}
+		errs.add("preset", "must be the name of a predefined palette")
+		return "", nil, errs, nil
+	}
+
+	generated, errs, err := generatePalette(c)
+	if len(errs) > 0 || err != nil {
+		return "", nil, errs, err
+	}
+	colors := make([]palette.RGB, len(generated.Colors))
+	for i, color := range generated.Colors {
+		colors[i] = color.RGB
+	}
+	return fmt.Sprintf("%s %s", generated.Mode, strings.TrimPrefix(generated.Base, "#")), colors, nil, nil
+}
+
+// ExportPalette renders a palette as a downloadable file. The palette comes
+// from colors, preset or the generator parameters, in that order.
+func ExportPalette(c echo.Context) error {
+	var errs validationErrors
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if !palette.ValidFormat(format) {
+		errs.add("format", "must be one of "+strings.Join(palette.Formats, ", "))
+	}
+	name := strings.TrimSpace(c.QueryParam("name"))
+	if len(name) > maxPaletteName {
+		errs.add("name", fmt.Sprintf("must be at most %d characters", maxPaletteName))
+	}
+
+	defaultName, colors, colorErrs, err := exportPaletteColors(c)
+	errs = append(errs, colorErrs...)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = defaultName
+	}
+
+	export, err := palette.Render(format, name, colors)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", export.Filename))
+	return c.Blob(http.StatusOK, export.ContentType, export.Data)
+}
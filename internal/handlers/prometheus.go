@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests that did not match a route, so unknown
+// paths cannot grow the number of series
+const unmatchedRoute = "unmatched"
+
+// httpMetrics are the request metrics exported in the Prometheus format,
+// next to the Go runtime and process collectors
+type httpMetrics struct {
+	registry *prometheus.Registry
+	handler  http.Handler
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+// newHTTPMetrics creates a registry with a latency histogram using the
+// given buckets, in seconds
+func newHTTPMetrics(buckets []float64) *httpMetrics {
+	m := &httpMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route and method.",
+			Buckets: buckets,
+		}, []string{"route", "method"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests being served, by route and method.",
+		}, []string{"route", "method"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests, m.duration, m.inFlight,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	// Responses are compressed by the gzip middleware when it is enabled
+	m.handler = promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics:  true,
+		DisableCompression: true,
+	})
+
+	return m
+}
+
+// routeLabel returns the route pattern of the request, such as
+// /api/v1/quotes/:id
+func routeLabel(c echo.Context) string {
+	if route := c.Path(); route != "" && route != "/*" {
+		return route
+	}
+	return unmatchedRoute
+}
+
+// track counts a request as in flight until the returned function records
+// its status
+func (m *httpMetrics) track(c echo.Context) func(status int) {
+	route, method := routeLabel(c), c.Request().Method
+	inFlight := m.inFlight.WithLabelValues(route, method)
+	inFlight.Inc()
+	start := time.Now()
+
+	return func(status int) {
+		inFlight.Dec()
+		m.requests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+		m.duration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// wantsPrometheus reports whether /metrics should use the Prometheus
+// exposition format: with format=prometheus, or an Accept header asking for
+// text/plain or OpenMetrics as scrapers send
+func wantsPrometheus(c echo.Context) (bool, validationErrors) {
+	switch strings.ToLower(c.QueryParam("format")) {
+	case "prometheus":
+		return true, nil
+	case "json":
+		return false, nil
+	case "":
+	default:
+		var errs validationErrors
+		errs.add("format", "must be one of json, prometheus")
+		return false, errs
+	}
+
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+	return strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text"), nil
+}
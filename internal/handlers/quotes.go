@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed data/quotes.json
+var seedQuotesJSON []byte
+
+// Pagination limits for the quote listing
+const (
+	defaultQuotesPerPage = 20
+	maxQuotesPerPage     = 100
+)
+
+// ErrQuoteNotFound is returned when a quote ID does not exist
+var ErrQuoteNotFound = errors.New("quote not found")
+
+// QuoteData represents an inspirational quote
+type QuoteData struct {
+	ID     int      `json:"id"`
+	Text   string   `json:"text"`
+	Author string   `json:"author"`
+	Tags   []string `json:"tags"`
+}
+
+// QuoteFilter selects and pages quotes in QuoteStore.List
+type QuoteFilter struct {
+	Author string // case-insensitive exact match
+	Tag    string // case-insensitive exact match
+	Offset int
+	Limit  int // 0 means no limit
+}
+
+// QuoteList is the response of the quote listing endpoint
+type QuoteList struct {
+	Quotes  []QuoteData `json:"quotes"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
+	Total   int         `json:"total"`
+}
+
+// TableKey makes the CSV form one row per quote
+func (QuoteList) TableKey() string { return "quotes" }
+
+// QuoteStore is a catalog of quotes
+type QuoteStore interface {
+	// List returns the quotes matching the filter, ordered by ID, and the
+	// total number of matches before paging
+	List(filter QuoteFilter) ([]QuoteData, int)
+	// Get returns the quote with the given ID
+	Get(id int) (QuoteData, error)
+	// Create stores a new quote and returns it with its assigned ID
+	Create(quote QuoteData) (QuoteData, error)
+	// Update replaces the quote with the given ID
+	Update(id int, quote QuoteData) (QuoteData, error)
+	// Delete removes the quote with the given ID
+	Delete(id int) error
+}
+
+// quoteStore is the store used by the quote handlers
+var quoteStore QuoteStore = mustSeedQuoteStore()
+
+// SetQuoteStore replaces the store used by the quote handlers
+func SetQuoteStore(store QuoteStore) {
+	quoteStore = store
+}
+
+// quotesPath is the canonical path of the quote collection, used to link to
+// created quotes
+var quotesPath = "/api/v1/quotes"
+
+// SetAPIPrefix sets the canonical prefix of the API, such as /api/v1, that
+// links to created resources start with
+func SetAPIPrefix(prefix string) {
+	quotesPath = prefix + "/quotes"
+}
+
+// MemoryQuoteStore is an in-memory QuoteStore
+type MemoryQuoteStore struct {
+	mu     sync.RWMutex
+	quotes map[int]QuoteData
+	nextID int
+}
+
+// NewMemoryQuoteStore creates a store holding the given quotes. IDs are
+// assigned in order, starting at 1.
+func NewMemoryQuoteStore(seed []QuoteData) *MemoryQuoteStore {
+	store := &MemoryQuoteStore{quotes: make(map[int]QuoteData), nextID: 1}
+	for _, quote := range seed {
+		_, _ = store.Create(quote)
+	}
+	return store
+}
+
+// mustSeedQuoteStore creates a memory store seeded from the embedded quotes
+func mustSeedQuoteStore() *MemoryQuoteStore {
+	var seed []QuoteData
+	if err := json.Unmarshal(seedQuotesJSON, &seed); err != nil {
+		panic("handlers: invalid embedded quotes: " + err.Error())
+	}
+	return NewMemoryQuoteStore(seed)
+}
+
+// List returns the quotes matching the filter
+func (s *MemoryQuoteStore) List(filter QuoteFilter) ([]QuoteData, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]QuoteData, 0, len(s.quotes))
+	for _, quote := range s.quotes {
+		if filter.Author != "" && !strings.EqualFold(quote.Author, filter.Author) {
+			continue
+		}
+		if filter.Tag != "" && !hasTag(quote.Tags, filter.Tag) {
+			continue
+		}
+		matches = append(matches, copyQuote(quote))
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	total := len(matches)
+	if filter.Offset < 0 || filter.Offset >= total {
+		return []QuoteData{}, total
+	}
+	matches = matches[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matches) {
+		matches = matches[:filter.Limit]
+	}
+	return matches, total
+}
+
+// Get returns the quote with the given ID
+func (s *MemoryQuoteStore) Get(id int) (QuoteData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	quote, ok := s.quotes[id]
+	if !ok {
+		return QuoteData{}, ErrQuoteNotFound
+	}
+	return copyQuote(quote), nil
+}
+
+// Create stores a new quote
+func (s *MemoryQuoteStore) Create(quote QuoteData) (QuoteData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	quote = copyQuote(quote)
+	quote.ID = s.nextID
+	s.nextID++
+	s.quotes[quote.ID] = quote
+	return copyQuote(quote), nil
+}
+
+// Update replaces the quote with the given ID
+func (s *MemoryQuoteStore) Update(id int, quote QuoteData) (QuoteData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.quotes[id]; !ok {
+		return QuoteData{}, ErrQuoteNotFound
+	}
+	quote = copyQuote(quote)
+	quote.ID = id
+	s.quotes[id] = quote
+	return copyQuote(quote), nil
+}
+
+// Delete removes the quote with the given ID
+func (s *MemoryQuoteStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.quotes[id]; !ok {
+		return ErrQuoteNotFound
+	}
+	delete(s.quotes, id)
+	return nil
+}
+
+// copyQuote returns a quote that shares no memory with the original
+func copyQuote(quote QuoteData) QuoteData {
+	quote.Tags = append([]string{}, quote.Tags...)
+	return quote
+}
+
+// hasTag reports whether tags contains tag, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// QuoteRequest is the request body for creating and updating quotes
+type QuoteRequest struct {
+	Text   string   `json:"text"`
+	Author string   `json:"author"`
+	Tags   []string `json:"tags"`
+}
+
+// bindQuote parses and validates a quote request body
+func bindQuote(c echo.Context) (QuoteData, validationErrors) {
+	var errs validationErrors
+	var req QuoteRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		errs.add("body", "must be a JSON object with text, author and tags")
+		return QuoteData{}, errs
+	}
+
+	quote := QuoteData{
+		Text:   strings.TrimSpace(req.Text),
+		Author: strings.TrimSpace(req.Author),
+		Tags:   []string{},
+	}
+	if quote.Text == "" {
+		errs.add("text", "is required")
+	}
+	if quote.Author == "" {
+		errs.add("author", "is required")
+	}
+	for _, tag := range req.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !hasTag(quote.Tags, tag) {
+			quote.Tags = append(quote.Tags, tag)
+		}
+	}
+
+	return quote, errs
+}
+
+// quoteID parses the :id path parameter
+func quoteID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return 0, echo.NewHTTPError(http.StatusNotFound, ErrQuoteNotFound.Error())
+	}
+	return id, nil
+}
+
+// quoteStoreError converts a store error into an HTTP error
+func quoteStoreError(err error) error {
+	if errors.Is(err, ErrQuoteNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return err
+}
+
+// selectQuote returns the quote requested by id, or picks one from the store
+// using the selection mode of the request
+func selectQuote(c echo.Context) (QuoteData, validationErrors, error) {
+	var errs validationErrors
+
+	if value := c.QueryParam("id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id < 1 {
+			errs.add("id", "must be a positive whole number")
+			return QuoteData{}, errs, nil
+		}
+		quote, err := quoteStore.Get(id)
+		return quote, nil, quoteStoreError(err)
+	}
+
+	quotes, _ := quoteStore.List(QuoteFilter{})
+	if len(quotes) == 0 {
+		return QuoteData{}, nil, echo.NewHTTPError(http.StatusNotFound, "No quotes available")
+	}
+
+	index, errs := selectIndex(c, "quote", len(quotes))
+	if len(errs) > 0 {
+		return QuoteData{}, errs, nil
+	}
+	return quotes[index], nil, nil
+}
+
+// GetQuote returns a random inspirational quote. Use id to fetch a specific
+// quote, mode=daily for the quote of the day or seed for a reproducible pick.
+func GetQuote(c echo.Context) error {
+	quote, errs, err := selectQuote(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	if err != nil {
+		return err
+	}
+
+	return render(c, http.StatusOK, quote, fragments.Quote(quote.Text, quote.Author))
+}
+
+// ListQuotes returns the quotes matching the author and tag filters, paged
+func ListQuotes(c echo.Context) error {
+	var errs validationErrors
+
+	page, perPage := 1, defaultQuotesPerPage
+	if value := c.QueryParam("page"); value != "" {
+		if parsed, err := strconv.Atoi(value); err != nil || parsed < 1 {
+			errs.add("page", "must be a positive whole number")
+		} else {
+			page = parsed
+		}
+	}
+	if value := c.QueryParam("per_page"); value != "" {
+		if parsed, err := strconv.Atoi(value); err != nil || parsed < 1 || parsed > maxQuotesPerPage {
+			errs.add("per_page", fmt.Sprintf("must be a whole number between 1 and %d", maxQuotesPerPage))
+		} else {
+			perPage = parsed
+		}
+	}
+	// Keep the offset of the page within an int
+	if maxPage := math.MaxInt / perPage; page > maxPage {
+		errs.add("page", fmt.Sprintf("must be at most %d", maxPage))
+	}
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	quotes, total := quoteStore.List(QuoteFilter{
+		Author: c.QueryParam("author"),
+		Tag:    c.QueryParam("tag"),
+		Offset: (page - 1) * perPage,
+		Limit:  perPage,
+	})
+
+	return render(c, http.StatusOK, QuoteList{
+		Quotes:  quotes,
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+	}, nil)
+}
+
+// GetQuoteByID returns a single quote
+func GetQuoteByID(c echo.Context) error {
+	id, err := quoteID(c)
+	if err != nil {
+		return err
+	}
+
+	quote, err := quoteStore.Get(id)
+	if err != nil {
+		return quoteStoreError(err)
+	}
+
+	return render(c, http.StatusOK, quote, fragments.Quote(quote.Text, quote.Author))
+}
+
+// CreateQuote adds a quote to the catalog
+func CreateQuote(c echo.Context) error {
+	quote, errs := bindQuote(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	created, err := quoteStore.Create(quote)
+	if err != nil {
+		return quoteStoreError(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/%d", quotesPath, created.ID))
+	return render(c, http.StatusCreated, created, fragments.Quote(created.Text, created.Author))
+}
+
+// UpdateQuote replaces a quote in the catalog
+func UpdateQuote(c echo.Context) error {
+	id, err := quoteID(c)
+	if err != nil {
+		return err
+	}
+
+	quote, errs := bindQuote(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	updated, err := quoteStore.Update(id, quote)
+	if err != nil {
+		return quoteStoreError(err)
+	}
+
+	return render(c, http.StatusOK, updated, fragments.Quote(updated.Text, updated.Author))
+}
+
+// DeleteQuote removes a quote from the catalog
+func DeleteQuote(c echo.Context) error {
+	id, err := quoteID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := quoteStore.Delete(id); err != nil {
+		return quoteStoreError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/random"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+)
+
+// Number types, distributions and limits for the random number endpoints
+const (
+	randomTypeInt   = "int"
+	randomTypeFloat = "float"
+
+	distributionUniform     = "uniform"
+	distributionNormal      = "normal"
+	distributionExponential = "exponential"
+
+	defaultRandomMin = 1
+	defaultRandomMax = 100
+	maxRandomCount   = 1000
+)
+
+// RandomNumbers is the response of the random number endpoint. Number is the
+// first of Numbers, kept for clients that ask for a single value.
+type RandomNumbers struct {
+	Number       any    `json:"number"`
+	Numbers      any    `json:"numbers"`
+	Min          any    `json:"min"`
+	Max          any    `json:"max"`
+	Type         string `json:"type"`
+	Distribution string `json:"distribution"`
+	Count        int    `json:"count"`
+	Unique       bool   `json:"unique"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+// TableKey makes the CSV form one row per number
+func (RandomNumbers) TableKey() string { return "numbers" }
+
+// DiceRolls is the response of the random number endpoint for dice notation.
+// Roll is the first of Rolls.
+type DiceRolls struct {
+	Notation  string            `json:"notation"`
+	Roll      random.DiceRoll   `json:"roll"`
+	Rolls     []random.DiceRoll `json:"rolls"`
+	Count     int               `json:"count"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// TableKey makes the CSV form one row per roll
+func (DiceRolls) TableKey() string { return "rolls" }
+
+// randomQuery holds the parsed parameters of a random number request. For
+// integers the bounds are kept both exactly and as floats for sampling.
+type randomQuery struct {
+	Type         string
+	MinInt       int64
+	MaxInt       int64
+	Min          float64
+	Max          float64
+	Count        int
+	Unique       bool
+	Distribution string
+	Mean         float64
+	StdDev       float64
+	Rate         float64
+}
+
+// parseRandomQuery reads and validates the random number parameters
+func parseRandomQuery(c echo.Context) (randomQuery, validationErrors) {
+	var errs validationErrors
+	q := randomQuery{
+		Type:         randomTypeInt,
+		MinInt:       defaultRandomMin,
+		MaxInt:       defaultRandomMax,
+		Count:        1,
+		Distribution: distributionUniform,
+	}
+
+	if value := c.QueryParam("type"); value != "" {
+		q.Type = strings.ToLower(value)
+		if q.Type != randomTypeInt && q.Type != randomTypeFloat {
+			errs.add("type", "must be int or float")
+			return q, errs
+		}
+	}
+
+	boundsOK := true
+	for _, bound := range []struct {
+		name  string
+		exact *int64
+		value *float64
+	}{{"min", &q.MinInt, &q.Min}, {"max", &q.MaxInt, &q.Max}} {
+		*bound.value = float64(*bound.exact)
+		raw := c.QueryParam(bound.name)
+		if raw == "" {
+			continue
+		}
+
+		if q.Type == randomTypeInt {
+			parsed, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				errs.add(bound.name, fmt.Sprintf("must be a whole number between %d and %d", int64(math.MinInt64), int64(math.MaxInt64)))
+				boundsOK = false
+				continue
+			}
+			*bound.exact, *bound.value = parsed, float64(parsed)
+		} else {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+				errs.add(bound.name, "must be a finite number")
+				boundsOK = false
+				continue
+			}
+			*bound.value = parsed
+		}
+	}
+	if boundsOK {
+		switch {
+		case q.Min > q.Max || q.Type == randomTypeInt && q.MinInt > q.MaxInt:
+			errs.add("max", "must be greater than or equal to min")
+		case math.IsInf(q.Max-q.Min, 0):
+			errs.add("max", "range between min and max is too large")
+		}
+	}
+
+	if value := c.QueryParam("count"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 || count > maxRandomCount {
+			errs.add("count", fmt.Sprintf("must be a whole number between 1 and %d", maxRandomCount))
+		} else {
+			q.Count = count
+		}
+	}
+
+	if value := c.QueryParam("unique"); value != "" {
+		unique, err := strconv.ParseBool(value)
+		if err != nil {
+			errs.add("unique", "must be true or false")
+		}
+		q.Unique = unique
+	}
+	if q.Unique && len(errs) == 0 {
+		// Compare in uint64 so the span of the full int64 range fits
+		span := uint64(q.MaxInt) - uint64(q.MinInt)
+		if q.Type == randomTypeFloat && q.Min == q.Max {
+			span = 0
+		}
+		if (q.Type == randomTypeInt || span == 0) && span < math.MaxUint64 && uint64(q.Count) > span+1 {
+			errs.add("count", fmt.Sprintf("cannot draw %d unique numbers from a range of %d", q.Count, span+1))
+		}
+	}
+
+	if value := c.QueryParam("distribution"); value != "" {
+		q.Distribution = strings.ToLower(value)
+	}
+	q.Mean = (q.Min + q.Max) / 2
+	q.StdDev = (q.Max - q.Min) / 6
+	q.Rate = 4 / (q.Max - q.Min)
+
+	switch q.Distribution {
+	case distributionUniform:
+	case distributionNormal:
+		if value, ok := parseFloatParam(c, "mean", &errs); ok {
+			q.Mean = value
+		}
+		if value, ok := parseFloatParam(c, "stddev", &errs); ok {
+			if value <= 0 {
+				errs.add("stddev", "must be greater than zero")
+			}
+			q.StdDev = value
+		}
+	case distributionExponential:
+		if value, ok := parseFloatParam(c, "rate", &errs); ok {
+			if value <= 0 {
+				errs.add("rate", "must be greater than zero")
+			}
+			q.Rate = value
+		}
+	default:
+		errs.add("distribution", "must be uniform, normal or exponential")
+	}
+
+	return q, errs
+}
+
+// parseFloatParam parses an optional finite float query parameter, recording
+// an error if it is malformed. ok is false if the parameter is absent or
+// invalid.
+func parseFloatParam(c echo.Context, name string, errs *validationErrors) (float64, bool) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+		errs.add(name, "must be a finite number")
+		return 0, false
+	}
+	return value, true
+}
+
+// draw returns one value following the requested distribution. Values from
+// the normal and exponential distributions are not bounded by min and max.
+func (q randomQuery) draw() float64 {
+	switch q.Distribution {
+	case distributionNormal:
+		return random.Normal(q.Mean, q.StdDev)
+	case distributionExponential:
+		return q.Min + random.Exponential(q.Rate)
+	}
+	return random.Float(q.Min, q.Max)
+}
+
+// generateNumbers draws the requested numbers. Values outside [min, max] are
+// redrawn, which truncates the normal and exponential distributions to the
+// range; if too many draws are rejected the parameters are reported as
+// invalid.
+func generateNumbers(q randomQuery) (RandomNumbers, validationErrors) {
+	result := RandomNumbers{
+		Type:         q.Type,
+		Distribution: q.Distribution,
+		Count:        q.Count,
+		Unique:       q.Unique,
+		Timestamp:    time.Now().Unix(),
+	}
+
+	maxDraws := q.Count*100 + 1000
+	// Ints are compared exactly: beyond 2^53 neighbours share a float
+	seenInts := make(map[int64]bool)
+	seenFloats := make(map[float64]bool)
+	var ints []int64
+	var floats []float64
+
+	for draws := 0; len(ints)+len(floats) < q.Count; draws++ {
+		if draws == maxDraws {
+			var errs validationErrors
+			errs.add("distribution", "too few values fall within min and max; widen the range or adjust mean, stddev or rate")
+			return RandomNumbers{}, errs
+		}
+
+		var value float64
+		var exact int64
+		switch {
+		case q.MinInt == q.MaxInt && q.Type == randomTypeInt, q.Min == q.Max && q.Type == randomTypeFloat:
+			exact, value = q.MinInt, q.Min
+		case q.Type == randomTypeInt && q.Distribution == distributionUniform:
+			exact = random.Int(q.MinInt, q.MaxInt)
+			value = float64(exact)
+		default:
+			value = q.draw()
+			if q.Type == randomTypeInt {
+				value = math.Round(value)
+			}
+			if value < q.Min || value > q.Max {
+				continue
+			}
+			exact = int64(value)
+			if value >= math.MaxInt64 {
+				// float64(MaxInt64) rounds up to 2^63, which int64 cannot hold
+				exact = math.MaxInt64
+			}
+		}
+
+		if q.Type == randomTypeInt {
+			if q.Unique && seenInts[exact] {
+				continue
+			}
+			seenInts[exact] = true
+			ints = append(ints, exact)
+		} else {
+			if q.Unique && seenFloats[value] {
+				continue
+			}
+			seenFloats[value] = true
+			floats = append(floats, value)
+		}
+	}
+
+	if q.Type == randomTypeInt {
+		result.Number, result.Numbers = ints[0], ints
+		result.Min, result.Max = q.MinInt, q.MaxInt
+	} else {
+		result.Number, result.Numbers = floats[0], floats
+		result.Min, result.Max = q.Min, q.Max
+	}
+	return result, nil
+}
+
+// rollDice rolls the dice notation in the dice parameter count times
+func rollDice(c echo.Context) (DiceRolls, validationErrors) {
+	var errs validationErrors
+
+	dice, err := random.ParseDice(c.QueryParam("dice"))
+	if err != nil {
+		errs.add("dice", err.Error())
+	}
+	count := 1
+	if value := c.QueryParam("count"); value != "" {
+		if count, err = strconv.Atoi(value); err != nil || count < 1 || count > maxRandomCount {
+			errs.add("count", fmt.Sprintf("must be a whole number between 1 and %d", maxRandomCount))
+		}
+	}
+	if len(errs) > 0 {
+		return DiceRolls{}, errs
+	}
+
+	rolls := make([]random.DiceRoll, count)
+	for i := range rolls {
+		rolls[i] = dice.Roll()
+	}
+	return DiceRolls{
+		Notation:  dice.String(),
+		Roll:      rolls[0],
+		Rolls:     rolls,
+		Count:     count,
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// GetRandomNumber generates cryptographically secure random numbers. It
+// supports batches with count, unique values, float output, uniform, normal
+// and exponential distributions, and dice notation via dice.
+func GetRandomNumber(c echo.Context) error {
+	if c.QueryParam("dice") != "" {
+		rolls, errs := rollDice(c)
+		if len(errs) > 0 {
+			return validationError(c, errs)
+		}
+
+		dice := make([]string, len(rolls.Roll.Dice))
+		for i, v := range rolls.Roll.Dice {
+			dice[i] = strconv.Itoa(v)
+		}
+		return render(c, http.StatusOK, rolls, fragments.DiceRoll(rolls.Notation, rolls.Roll.Total, dice))
+	}
+
+	q, errs := parseRandomQuery(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	result, errs := generateNumbers(q)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	var numbers []string
+	switch values := result.Numbers.(type) {
+	case []int64:
+		for _, v := range values {
+			numbers = append(numbers, strconv.FormatInt(v, 10))
+		}
+	case []float64:
+		for _, v := range values {
+			numbers = append(numbers, strconv.FormatFloat(v, 'f', 4, 64))
+		}
+	}
+
+	return render(c, http.StatusOK, result, fragments.RandomNumbers(numbers))
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestGetRandomNumberUniqueBounds(t *testing.T) {
+	tests := []struct {
+		query    string
+		status   int
+		min, max int64
+	}{
+		{query: "min=1&max=10&count=10&unique=true", status: http.StatusOK, min: 1, max: 10},
+		{query: "min=1&max=10&count=11&unique=true", status: http.StatusBadRequest},
+		{query: "min=5&max=5&count=1&unique=true", status: http.StatusOK, min: 5, max: 5},
+		{query: "min=5&max=5&count=2&unique=true", status: http.StatusBadRequest},
+		{query: "min=5&max=5&count=2", status: http.StatusOK, min: 5, max: 5},
+		{query: "min=-9223372036854775808&max=-9223372036854775806&count=3&unique=true",
+			status: http.StatusOK, min: -9223372036854775808, max: -9223372036854775806},
+		{query: "min=9223372036854775805&max=9223372036854775807&count=4&unique=true", status: http.StatusBadRequest},
+		{query: "min=-9223372036854775808&max=9223372036854775807&count=1000&unique=true",
+			status: http.StatusOK, min: -9223372036854775808, max: 9223372036854775807},
+		{query: "type=float&min=1&max=1&count=2&unique=true", status: http.StatusBadRequest},
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.GET("/random", GetRandomNumber)
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/random?"+tt.query, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Numbers []json.Number `json:"numbers"`
+				Unique  bool          `json:"unique"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			seen := map[int64]bool{}
+			for _, raw := range body.Numbers {
+				n, err := strconv.ParseInt(raw.String(), 10, 64)
+				if err != nil {
+					t.Fatalf("number %s: %v", raw, err)
+				}
+				if n < tt.min || n > tt.max {
+					t.Errorf("number %d outside [%d, %d]", n, tt.min, tt.max)
+				}
+				if body.Unique && seen[n] {
+					t.Errorf("number %d drawn twice", n)
+				}
+				seen[n] = true
+			}
+		})
+	}
+}
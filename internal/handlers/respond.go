@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Damianko135/playground-go/internal/respond"
+	"github.com/Damianko135/playground-go/internal/utils"
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// formatContextKey holds the default format set by PreferFragments
+const formatContextKey = "response_format"
+
+// PreferFragments makes HTML fragments the default for the routes it wraps,
+// so /htmx/* keeps returning HTML without an Accept header. ?format= still
+// takes precedence.
+func PreferFragments(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set(formatContextKey, respond.HTML)
+		return next(c)
+	}
+}
+
+// responseFormat picks the format for a response. In order: the ?format=
+// parameter, HTML for HTMX requests and /htmx/* routes, then the Accept
+// header. HTML is only available when the handler has a fragment.
+func responseFormat(c echo.Context, html bool) (string, validationErrors, error) {
+	if format := strings.ToLower(c.QueryParam("format")); format != "" {
+		if !respond.Valid(format) || (format == respond.HTML && !html) {
+			var errs validationErrors
+			errs.add("format", "must be one of "+strings.Join(respond.Available(html), ", "))
+			return "", errs, nil
+		}
+		return format, nil, nil
+	}
+
+	if html && (c.Get(formatContextKey) == respond.HTML || c.Request().Header.Get("HX-Request") == "true") {
+		return respond.HTML, nil, nil
+	}
+
+	format, err := respond.Negotiate(c.Request(), html)
+	if errors.Is(err, respond.ErrNotAcceptable) {
+		return "", nil, echo.NewHTTPError(http.StatusNotAcceptable,
+			"Supported formats: "+strings.Join(respond.Available(html), ", "))
+	}
+	return format, nil, err
+}
+
+// render writes data in the negotiated format. Fragment renders the HTML
+// version; pass nil for resources without one.
+func render(c echo.Context, status int, data interface{}, fragment templ.Component) error {
+	varyByFormat(c)
+
+	format, errs, err := responseFormat(c, fragment != nil)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case respond.JSON:
+		return c.JSON(status, data)
+	case respond.HTML:
+		c.Response().Header().Set(echo.HeaderContentType, respond.ContentTypes[respond.HTML])
+		c.Response().WriteHeader(status)
+		return utils.Render(c, fragment)
+	}
+
+	var buf bytes.Buffer
+	if err := respond.Encode(&buf, format, data); err != nil {
+		return err
+	}
+	return c.Blob(status, respond.ContentTypes[format], buf.Bytes())
+}
+
+// varyByFormat marks the response as depending on the headers that pick
+// its format
+func varyByFormat(c echo.Context) {
+	header := c.Response().Header()
+	for _, name := range []string{"Accept", "HX-Request"} {
+		found := false
+		for _, value := range header.Values(echo.HeaderVary) {
+			if value == name {
+				found = true
+			}
+		}
+		if !found {
+			header.Add(echo.HeaderVary, name)
+		}
+	}
+}
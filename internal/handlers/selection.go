@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/selector"
+	"github.com/labstack/echo/v4"
+)
+
+// Selection modes accepted by the mode query parameter
+const (
+	selectionRandom = "random"
+	selectionDaily  = "daily"
+)
+
+// contentSelector picks quotes, jokes and palettes
+var contentSelector = selector.New(time.UTC)
+
+// SetSelector replaces the selector used by the content handlers
+func SetSelector(s *selector.Selector) {
+	contentSelector = s
+}
+
+// selectIndex picks an item from a catalog of n items for the request.
+// mode=daily returns the same item all day; otherwise a seed parameter makes
+// the pick reproducible, and without one the pick is random and never
+// repeats the client's previous item.
+func selectIndex(c echo.Context, namespace string, n int) (int, validationErrors) {
+	var errs validationErrors
+
+	switch mode := c.QueryParam("mode"); mode {
+	case selectionDaily:
+		return contentSelector.Daily(namespace, n, time.Now()), nil
+	case "", selectionRandom:
+	default:
+		errs.add("mode", "must be one of: random, daily")
+		return 0, errs
+	}
+
+	if seed := c.QueryParam("seed"); seed != "" {
+		return contentSelector.Seeded(namespace, seed, n), nil
+	}
+
+	return contentSelector.Random(namespace, c.RealIP(), n), nil
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/sysstats"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// statUnavailable is reported for metrics that cannot be read on this host
+const statUnavailable = "unavailable"
+
+// statsSampler provides the host readings for the stats handlers
+var statsSampler *sysstats.Sampler
+
+// SetStatsSampler sets the sampler used by the stats handlers
+func SetStatsSampler(sampler *sysstats.Sampler) {
+	statsSampler = sampler
+}
+
+// latestSnapshot returns the latest host snapshot, or an empty one if no
+// sampler is configured
+func latestSnapshot() sysstats.Snapshot {
+	if statsSampler == nil {
+		return sysstats.Snapshot{SampledAt: time.Now()}
+	}
+	return statsSampler.Latest()
+}
+
+// formatPercent formats a percentage reading
+func formatPercent(r sysstats.Reading) string {
+	if !r.OK {
+		return statUnavailable
+	}
+	return fmt.Sprintf("%.1f%%", r.Value)
+}
+
+// formatRate formats a bytes-per-second reading
+func formatRate(r sysstats.Reading) string {
+	if !r.OK {
+		return statUnavailable
+	}
+
+	units := []string{"B/s", "KB/s", "MB/s", "GB/s"}
+	value := r.Value
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+// systemStats builds the stats response from the latest snapshot
+func systemStats() map[string]interface{} {
+	snapshot := latestSnapshot()
+
+	return map[string]interface{}{
+		"cpu_usage":    formatPercent(snapshot.CPU),
+		"memory_usage": formatPercent(snapshot.Memory),
+		"disk_usage":   formatPercent(snapshot.Disk),
+		"network_in":   formatRate(snapshot.NetworkIn),
+		"network_out":  formatRate(snapshot.NetworkOut),
+		"uptime":       time.Since(processStart).String(),
+		"sampled_at":   snapshot.SampledAt.Unix(),
+		"timestamp":    time.Now().Unix(),
+	}
+}
+
+// GetSystemStats returns system performance statistics
+func GetSystemStats(c echo.Context) error {
+	stats := systemStats()
+
+	return render(c, http.StatusOK, stats, fragments.SystemStats(
+		stats["cpu_usage"].(string), stats["memory_usage"].(string), stats["disk_usage"].(string),
+		stats["network_in"].(string), stats["network_out"].(string), stats["uptime"].(string),
+	))
+}
+
+// StatsPoint is a single value in a stats time series
+type StatsPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// StatsHistory is the response of the stats history endpoint
+type StatsHistory struct {
+	Metric   string       `json:"metric"`
+	Unit     string       `json:"unit"`
+	Interval string       `json:"interval"`
+	Since    time.Time    `json:"since"`
+	Points   []StatsPoint `json:"points"`
+}
+
+// TableKey makes the CSV form one row per sample
+func (StatsHistory) TableKey() string { return "points" }
+
+// statsUnit returns the unit of a metric's values
+func statsUnit(metric string) string {
+	switch metric {
+	case sysstats.MetricNetworkIn, sysstats.MetricNetworkOut:
+		return "bytes_per_second"
+	default:
+		return "percent"
+	}
+}
+
+// parseSince parses the since query parameter. It accepts an RFC 3339
+// timestamp, unix seconds, or a duration such as "15m" meaning that long ago.
+// An empty value selects the full retention window.
+func parseSince(value string, now time.Time, retention time.Duration) (time.Time, bool) {
+	if value == "" {
+		return now.Add(-retention), true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0), true
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), true
+	}
+	return time.Time{}, false
+}
+
+// statsHistory parses the metric and since parameters and collects the
+// matching points. Samples where the metric was unavailable are skipped.
+func statsHistory(c echo.Context) (StatsHistory, validationErrors) {
+	var errs validationErrors
+
+	metric := c.QueryParam("metric")
+	if metric == "" {
+		metric = sysstats.MetricCPU
+	}
+	if _, ok := (sysstats.Snapshot{}).Metric(metric); !ok {
+		errs.add("metric", "must be one of: "+strings.Join(sysstats.Metrics, ", "))
+	}
+
+	var retention time.Duration
+	if statsSampler != nil {
+		retention = statsSampler.Retention()
+	}
+	since, ok := parseSince(c.QueryParam("since"), time.Now(), retention)
+	if !ok {
+		errs.add("since", "must be an RFC 3339 timestamp, unix seconds or a duration such as 15m")
+	}
+
+	if len(errs) > 0 {
+		return StatsHistory{}, errs
+	}
+
+	history := StatsHistory{
+		Metric: metric,
+		Unit:   statsUnit(metric),
+		Since:  since,
+		Points: []StatsPoint{},
+	}
+	if statsSampler == nil {
+		return history, nil
+	}
+
+	history.Interval = statsSampler.Interval().String()
+	for _, snapshot := range statsSampler.History(since) {
+		if reading, _ := snapshot.Metric(metric); reading.OK {
+			history.Points = append(history.Points, StatsPoint{
+				Timestamp: snapshot.SampledAt,
+				Value:     math.Round(reading.Value*100) / 100,
+			})
+		}
+	}
+
+	return history, nil
+}
+
+// GetStatsHistory returns the recorded time series for a system metric
+func GetStatsHistory(c echo.Context) error {
+	history, errs := statsHistory(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	return render(c, http.StatusOK, history, sparklineFragment(history))
+}
+
+// Sparkline dimensions in SVG user units
+const (
+	sparklineWidth  = 200.0
+	sparklineHeight = 40.0
+)
+
+// sparklineFragment renders a metric's history as an SVG sparkline fragment
+// for HTMX
+func sparklineFragment(history StatsHistory) templ.Component {
+	if len(history.Points) < 2 {
+		return fragments.SparklineCollecting()
+	}
+
+	// Percentages use a fixed 0-100 scale so the line is comparable over
+	// time; rates are scaled to their own peak
+	minValue, maxValue := history.Points[0].Value, history.Points[0].Value
+	for _, p := range history.Points {
+		minValue = math.Min(minValue, p.Value)
+		maxValue = math.Max(maxValue, p.Value)
+	}
+	scale := 100.0
+	if history.Unit != "percent" {
+		scale = math.Max(maxValue, 1)
+	}
+
+	format := func(v float64) string {
+		if history.Unit == "percent" {
+			return formatPercent(sysstats.Reading{Value: v, OK: true})
+		}
+		return formatRate(sysstats.Reading{Value: v, OK: true})
+	}
+
+	start := history.Points[0].Timestamp
+	span := history.Points[len(history.Points)-1].Timestamp.Sub(start).Seconds()
+	points := make([]string, len(history.Points))
+	for i, p := range history.Points {
+		x := 0.0
+		if span > 0 {
+			x = p.Timestamp.Sub(start).Seconds() / span * sparklineWidth
+		}
+		y := sparklineHeight - p.Value/scale*sparklineHeight
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	return fragments.Sparkline(sparklineWidth, sparklineHeight, strings.Join(points, " "),
+		format(minValue), format(history.Points[len(history.Points)-1].Value), format(maxValue))
+}
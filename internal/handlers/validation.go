@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FieldError describes a single invalid request parameter
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validationErrors collects field errors while parsing a request
+type validationErrors []FieldError
+
+// add records an error for the given field
+func (v *validationErrors) add(field, message string) {
+	*v = append(*v, FieldError{Field: field, Message: message})
+}
+
+// Error implements the error interface
+func (v validationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, fe := range v {
+		messages[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// validationError writes a 400 problem response listing the field errors
+func validationError(c echo.Context, fields validationErrors) error {
+	p := newProblem(c, http.StatusBadRequest, "One or more request parameters are invalid")
+	p.Errors = fields
+	return writeError(c, p, nil)
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/config"
+	"github.com/Damianko135/playground-go/internal/geo"
+	"github.com/Damianko135/playground-go/internal/logging"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// WeatherData represents weather information
+type WeatherData struct {
+	Location    string  `json:"location"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Units       string  `json:"units"`
+	Temperature float64 `json:"temperature"`
+	Description string  `json:"description"`
+	Humidity    int     `json:"humidity"`
+	WindSpeed   float64 `json:"wind_speed"`
+	Icon        string  `json:"icon"`
+	Timestamp   string  `json:"timestamp"`
+}
+
+// Supported unit systems for weather queries
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// defaultWeatherLocation is used when a request does not specify a location
+const defaultWeatherLocation = "Amsterdam, NL"
+
+// nearestCityRadiusKm is how close coordinates must be to a gazetteer city
+// to be labelled with its name
+const nearestCityRadiusKm = 25.0
+
+// WeatherQuery describes a resolved weather lookup
+type WeatherQuery struct {
+	Location  string
+	Latitude  float64
+	Longitude float64
+	Units     string
+}
+
+// WeatherProvider is a source of weather data
+type WeatherProvider interface {
+	// Current returns the current weather conditions for a location
+	Current(ctx context.Context, query WeatherQuery) (WeatherData, error)
+	// Forecast returns the daily forecast for a location, starting today
+	Forecast(ctx context.Context, query WeatherQuery, days int) ([]ForecastDay, error)
+}
+
+// weatherProvider is the provider used by the weather handlers
+var weatherProvider WeatherProvider = NewStaticWeatherProvider()
+
+// SetWeatherProvider replaces the provider used by the weather handlers
+func SetWeatherProvider(provider WeatherProvider) {
+	weatherProvider = provider
+}
+
+// NewWeatherProvider creates the weather provider selected by the configuration
+func NewWeatherProvider(cfg config.WeatherConfig) (WeatherProvider, error) {
+	switch cfg.Provider {
+	case "", "static":
+		return NewStaticWeatherProvider(), nil
+	case "http":
+		return NewHTTPWeatherProvider(cfg.BaseURL, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown weather provider %q", cfg.Provider)
+	}
+}
+
+// StaticWeatherProvider generates deterministic mock weather data. Values
+// depend on the coordinates and the current date, so each location gets its
+// own plausible conditions that change from day to day.
+type StaticWeatherProvider struct{}
+
+// NewStaticWeatherProvider creates a provider that serves mock data
+func NewStaticWeatherProvider() *StaticWeatherProvider {
+	return &StaticWeatherProvider{}
+}
+
+// weatherCondition pairs a human readable description with an icon name
+type weatherCondition struct {
+	Description string
+	Icon        string
+}
+
+var weatherConditions = []weatherCondition{
+	{Description: "Clear sky", Icon: "clear"},
+	{Description: "Partly cloudy", Icon: "partly-cloudy"},
+	{Description: "Overcast", Icon: "cloudy"},
+	{Description: "Light rain", Icon: "rain"},
+	{Description: "Thunderstorm", Icon: "storm"},
+	{Description: "Fog", Icon: "fog"},
+}
+
+var snowCondition = weatherCondition{Description: "Snow", Icon: "snow"}
+
+// mockWeatherDay holds the generated metric conditions for one day
+type mockWeatherDay struct {
+	Temperature  float64
+	Spread       float64
+	Condition    weatherCondition
+	Humidity     int
+	WindSpeed    float64
+	PrecipChance int
+}
+
+// mockWeather generates the conditions for a location on a given date. Both
+// Current and Forecast derive from this so the two views always agree.
+func mockWeather(query WeatherQuery, date time.Time) mockWeatherDay {
+	r := mockWeatherRand(query, date.Format("2006-01-02"))
+
+	// Warmer near the equator, with some daily variation
+	temperature := 30 - 0.45*math.Abs(query.Latitude) + r.Float64()*8 - 4
+	condition := weatherConditions[r.IntN(len(weatherConditions))]
+	if temperature < 1 && condition.Icon == "rain" {
+		condition = snowCondition
+	}
+
+	day := mockWeatherDay{
+		Temperature: temperature,
+		Condition:   condition,
+		Humidity:    40 + r.IntN(55),
+		WindSpeed:   2 + r.Float64()*30,
+		Spread:      2 + r.Float64()*5,
+	}
+
+	switch condition.Icon {
+	case "rain", "storm", "snow":
+		day.PrecipChance = 60 + r.IntN(41)
+	case "cloudy", "fog":
+		day.PrecipChance = 20 + r.IntN(30)
+	default:
+		day.PrecipChance = r.IntN(20)
+	}
+
+	return day
+}
+
+// Current returns mock weather data for the query
+func (p *StaticWeatherProvider) Current(ctx context.Context, query WeatherQuery) (WeatherData, error) {
+	now := time.Now()
+	day := mockWeather(query, now)
+
+	weather := WeatherData{
+		Location:    query.Location,
+		Latitude:    query.Latitude,
+		Longitude:   query.Longitude,
+		Units:       UnitsMetric,
+		Temperature: roundTenth(day.Temperature),
+		Description: day.Condition.Description,
+		Humidity:    day.Humidity,
+		WindSpeed:   roundTenth(day.WindSpeed),
+		Icon:        day.Condition.Icon,
+		Timestamp:   now.Format("2006-01-02 15:04:05"),
+	}
+
+	return convertWeatherUnits(weather, query.Units), nil
+}
+
+// mockWeatherRand returns a random source seeded from the query and a key,
+// so the same location and key always produce the same values
+func mockWeatherRand(query WeatherQuery, key string) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%.2f,%.2f,%s", query.Latitude, query.Longitude, key)
+	seed := h.Sum64()
+	return rand.New(rand.NewPCG(seed, seed>>1))
+}
+
+// convertWeatherUnits converts metric weather data to the requested units
+func convertWeatherUnits(weather WeatherData, units string) WeatherData {
+	if units != UnitsImperial || weather.Units == UnitsImperial {
+		return weather
+	}
+
+	weather.Units = UnitsImperial
+	weather.Temperature = roundTenth(celsiusToFahrenheit(weather.Temperature))
+	weather.WindSpeed = roundTenth(kmhToMph(weather.WindSpeed))
+	return weather
+}
+
+func celsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+func kmhToMph(kmh float64) float64 {
+	return kmh / 1.609344
+}
+
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// temperatureSymbol returns the temperature unit symbol for a unit system
+func temperatureSymbol(units string) string {
+	if units == UnitsImperial {
+		return "°F"
+	}
+	return "°C"
+}
+
+// speedSymbol returns the wind speed unit for a unit system
+func speedSymbol(units string) string {
+	if units == UnitsImperial {
+		return "mph"
+	}
+	return "km/h"
+}
+
+// HTTPWeatherProvider fetches weather data from an upstream HTTP service.
+// The upstream is expected to serve WeatherData JSON at {baseURL}/current and
+// a ForecastDay JSON array at {baseURL}/forecast, and to accept the lat, lon,
+// units and location query parameters.
+type HTTPWeatherProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPWeatherProvider creates a provider backed by the service at baseURL
+func NewHTTPWeatherProvider(baseURL string, timeout time.Duration) (*HTTPWeatherProvider, error) {
+	if baseURL == "" {
+		return nil, errors.New("weather base URL is required")
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid weather base URL: %w", err)
+	}
+
+	return &HTTPWeatherProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client: &http.Client{
+			Timeout: timeout,
+			// Trace upstream calls and pass the trace context on
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}, nil
+}
+
+// Current fetches the current weather conditions from the upstream service
+func (p *HTTPWeatherProvider) Current(ctx context.Context, query WeatherQuery) (WeatherData, error) {
+	var weather WeatherData
+	if err := p.get(ctx, "/current", upstreamParams(query), &weather); err != nil {
+		return weather, err
+	}
+
+	// Fill in anything the upstream left out from the resolved query
+	if weather.Location == "" {
+		weather.Location = query.Location
+	}
+	if weather.Latitude == 0 && weather.Longitude == 0 {
+		weather.Latitude, weather.Longitude = query.Latitude, query.Longitude
+	}
+	if weather.Units == "" {
+		weather.Units = query.Units
+	}
+	if weather.Timestamp == "" {
+		weather.Timestamp = time.Now().Format("2006-01-02 15:04:05")
+	}
+
+	return weather, nil
+}
+
+// upstreamParams encodes a resolved query for the upstream service
+func upstreamParams(query WeatherQuery) url.Values {
+	params := url.Values{}
+	params.Set("location", query.Location)
+	params.Set("lat", strconv.FormatFloat(query.Latitude, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(query.Longitude, 'f', -1, 64))
+	params.Set("units", query.Units)
+	return params
+}
+
+// get performs a GET request against the upstream and decodes the JSON body
+func (p *HTTPWeatherProvider) get(ctx context.Context, path string, params url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("weather request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("weather upstream returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid weather response: %w", err)
+	}
+
+	return nil
+}
+
+// parseWeatherQuery resolves the location, lat/lon and units query
+// parameters. Coordinates take precedence over a location name; without
+// either, the default location is used.
+func parseWeatherQuery(c echo.Context) (WeatherQuery, validationErrors) {
+	var errs validationErrors
+	query := WeatherQuery{Units: UnitsMetric}
+
+	if units := c.QueryParam("units"); units != "" {
+		switch units {
+		case UnitsMetric, UnitsImperial:
+			query.Units = units
+		default:
+			errs.add("units", "must be one of: metric, imperial")
+		}
+	}
+
+	latParam, lonParam := c.QueryParam("lat"), c.QueryParam("lon")
+	if latParam != "" || lonParam != "" {
+		lat, latErr := strconv.ParseFloat(latParam, 64)
+		lon, lonErr := strconv.ParseFloat(lonParam, 64)
+		switch {
+		case latParam == "":
+			errs.add("lat", "is required when lon is given")
+		case latErr != nil || math.IsNaN(lat) || math.IsInf(lat, 0) || lat < -90 || lat > 90:
+			errs.add("lat", "must be a number between -90 and 90")
+		}
+		switch {
+		case lonParam == "":
+			errs.add("lon", "is required when lat is given")
+		case lonErr != nil || math.IsNaN(lon) || math.IsInf(lon, 0) || lon < -180 || lon > 180:
+			errs.add("lon", "must be a number between -180 and 180")
+		}
+		if len(errs) > 0 {
+			return query, errs
+		}
+
+		query.Latitude, query.Longitude = lat, lon
+		if city, distance := geo.Nearest(lat, lon); distance <= nearestCityRadiusKm {
+			query.Location = city.Label()
+		} else {
+			query.Location = fmt.Sprintf("%.4f, %.4f", lat, lon)
+		}
+		return query, errs
+	}
+
+	location := c.QueryParam("location")
+	if location == "" {
+		location = defaultWeatherLocation
+	}
+	city, ok := geo.Lookup(location)
+	if !ok {
+		errs.add("location", "unknown location "+strconv.Quote(location))
+		return query, errs
+	}
+	query.Location = city.Label()
+	query.Latitude, query.Longitude = city.Latitude, city.Longitude
+
+	return query, errs
+}
+
+// currentWeather loads the current weather for a resolved query
+func currentWeather(c echo.Context, query WeatherQuery) (WeatherData, error) {
+	weather, err := weatherProvider.Current(c.Request().Context(), query)
+	if err != nil {
+		logging.FromContext(c.Request().Context()).Error("weather provider failed", "error", err)
+		return weather, echo.NewHTTPError(http.StatusBadGateway, "Weather data unavailable")
+	}
+	return weather, nil
+}
+
+// GetWeather returns the current weather for the requested location
+func GetWeather(c echo.Context) error {
+	query, errs := parseWeatherQuery(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	weather, err := currentWeather(c, query)
+	if err != nil {
+		return err
+	}
+
+	return render(c, http.StatusOK, weather, fragments.Weather(
+		weather.Location,
+		fmt.Sprintf("%.1f%s", weather.Temperature, temperatureSymbol(weather.Units)),
+		weather.Description,
+		weather.Humidity,
+		fmt.Sprintf("%.1f %s", weather.WindSpeed, speedSymbol(weather.Units)),
+		weather.Timestamp,
+	))
+}
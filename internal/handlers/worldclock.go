@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Damianko135/playground-go/internal/config"
+	"github.com/Damianko135/playground-go/views/fragments"
+	"github.com/labstack/echo/v4"
+)
+
+// maxClockZones limits how many zones a single request may ask for
+const maxClockZones = 24
+
+// timeInputLayouts are the accepted layouts for times without an offset,
+// interpreted in the source time zone
+var timeInputLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ZoneTime describes the time in one time zone
+type ZoneTime struct {
+	Zone          string `json:"zone"`
+	Label         string `json:"label"`
+	Time          string `json:"time"`
+	Date          string `json:"date"`
+	ISOTime       string `json:"iso_time"`
+	Abbreviation  string `json:"abbreviation"`
+	UTCOffset     string `json:"utc_offset"`
+	OffsetSeconds int    `json:"offset_seconds"`
+	DST           bool   `json:"dst"`
+}
+
+// WorldClock is the current time in a list of time zones, in request order
+type WorldClock struct {
+	Zones     []ZoneTime `json:"zones"`
+	Timestamp int64      `json:"timestamp"`
+	ISOTime   string     `json:"iso_time"`
+}
+
+// TableKey makes the CSV form one row per zone
+func (WorldClock) TableKey() string { return "zones" }
+
+// TimeConversion is a point in time expressed in a source and target zones
+type TimeConversion struct {
+	Input   string     `json:"input"`
+	Instant string     `json:"instant"`
+	From    ZoneTime   `json:"from"`
+	To      []ZoneTime `json:"to"`
+}
+
+// TableKey makes the CSV form one row per target zone
+func (TimeConversion) TableKey() string { return "to" }
+
+// clockZones are the zones shown when a request does not list any
+var clockZones = config.DefaultClockZones
+
+// zoneCache holds loaded locations by IANA name so each zone is read from
+// the time zone database only once
+var zoneCache sync.Map
+
+// SetClockZones sets the zones shown when a request does not list any. It
+// returns an error if a zone cannot be loaded.
+func SetClockZones(zones []string) error {
+	for _, zone := range zones {
+		if _, err := loadZone(zone); err != nil {
+			return err
+		}
+	}
+	clockZones = zones
+	return nil
+}
+
+// loadZone returns the location for an IANA zone name, loading it once
+func loadZone(name string) (*time.Location, error) {
+	if loc, ok := zoneCache.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	// "" and "Local" are accepted by time.LoadLocation but are not IANA names
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+
+	zoneCache.Store(name, loc)
+	return loc, nil
+}
+
+// parseZones reads a comma-separated zone list from the named parameter,
+// falling back to the configured zones. Duplicates are dropped and the
+// order is kept.
+func parseZones(c echo.Context, param string, errs *validationErrors) []*time.Location {
+	names := clockZones
+	if value := c.QueryParam(param); value != "" {
+		names = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" && !containsString(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 || len(names) > maxClockZones {
+		errs.add(param, fmt.Sprintf("must list between 1 and %d time zones", maxClockZones))
+		return nil
+	}
+
+	locations := make([]*time.Location, 0, len(names))
+	for _, name := range names {
+		loc, err := loadZone(name)
+		if err != nil {
+			errs.add(param, err.Error())
+			continue
+		}
+		locations = append(locations, loc)
+	}
+	return locations
+}
+
+// zoneLabel turns an IANA name into a display label, such as "New York" for
+// America/New_York
+func zoneLabel(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// zoneTime describes t in the given location
+func zoneTime(t time.Time, loc *time.Location) ZoneTime {
+	local := t.In(loc)
+	abbreviation, offset := local.Zone()
+	return ZoneTime{
+		Zone:          loc.String(),
+		Label:         zoneLabel(loc.String()),
+		Time:          local.Format("15:04:05"),
+		Date:          local.Format("2006-01-02"),
+		ISOTime:       local.Format(time.RFC3339),
+		Abbreviation:  abbreviation,
+		UTCOffset:     local.Format("-07:00"),
+		OffsetSeconds: offset,
+		DST:           local.IsDST(),
+	}
+}
+
+// worldClock returns the current time in the requested zones
+func worldClock(c echo.Context) (WorldClock, validationErrors) {
+	var errs validationErrors
+	locations := parseZones(c, "zones", &errs)
+	if len(errs) > 0 {
+		return WorldClock{}, errs
+	}
+
+	now := time.Now()
+	clock := WorldClock{
+		Zones:     make([]ZoneTime, len(locations)),
+		Timestamp: now.Unix(),
+		ISOTime:   now.Format(time.RFC3339),
+	}
+	for i, loc := range locations {
+		clock.Zones[i] = zoneTime(now, loc)
+	}
+	return clock, nil
+}
+
+// parseTimeInput parses an RFC 3339 time, a date and time without offset or
+// a bare HH:MM time on today's date. Times without an offset are read in loc.
+func parseTimeInput(value string, loc *time.Location, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range timeInputLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse("15:04", value); err == nil {
+		today := now.In(loc)
+		return time.Date(today.Year(), today.Month(), today.Day(), t.Hour(), t.Minute(), 0, 0, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("must be RFC 3339, YYYY-MM-DDTHH:MM or HH:MM")
+}
+
+// GetTimeZones returns the current time in the zones listed in zones, or in
+// the configured zones, with UTC offsets and DST flags
+func GetTimeZones(c echo.Context) error {
+	clock, errs := worldClock(c)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	return render(c, http.StatusOK, clock, fragments.WorldClock(clockFragmentZones(clock.Zones)))
+}
+
+// ConvertTime converts a time from one zone to one or more others. The time
+// defaults to now, from to UTC and to to the configured zones.
+func ConvertTime(c echo.Context) error {
+	var errs validationErrors
+
+	fromName := c.QueryParam("from")
+	if fromName == "" {
+		fromName = "UTC"
+	}
+	from, err := loadZone(fromName)
+	if err != nil {
+		errs.add("from", err.Error())
+	}
+	targets := parseZones(c, "to", &errs)
+	if len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	now := time.Now()
+	input := c.QueryParam("time")
+	t := now
+	if input != "" {
+		if t, err = parseTimeInput(input, from, now); err != nil {
+			errs.add("time", err.Error())
+			return validationError(c, errs)
+		}
+	}
+
+	conversion := TimeConversion{
+		Input:   input,
+		Instant: t.UTC().Format(time.RFC3339),
+		From:    zoneTime(t, from),
+		To:      make([]ZoneTime, len(targets)),
+	}
+	for i, loc := range targets {
+		conversion.To[i] = zoneTime(t, loc)
+	}
+
+	return render(c, http.StatusOK, conversion, nil)
+}
+
+// clockFragmentZones converts zone times for the world clock fragment
+func clockFragmentZones(times []ZoneTime) []fragments.ClockZone {
+	zones := make([]fragments.ClockZone, len(times))
+	for i, zone := range times {
+		zones[i] = fragments.ClockZone{
+			Zone:         zone.Zone,
+			Label:        zone.Label,
+			Time:         zone.Time,
+			Abbreviation: zone.Abbreviation,
+			UTCOffset:    zone.UTCOffset,
+			DST:          zone.DST,
+		}
+	}
+	return zones
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/labstack/gommon/log"
+)
+
+// EchoLogger implements echo.Logger on top of a slog logger, so messages
+// from Echo itself end up in the same structured stream. Levels are
+// filtered by the slog handler; SetLevel, SetOutput and SetHeader only
+// exist to satisfy the interface.
+type EchoLogger struct {
+	logger *slog.Logger
+	output io.Writer
+	prefix string
+	level  log.Lvl
+}
+
+// NewEchoLogger creates an Echo logger writing through logger. Output
+// reports w, the writer of logger's handler.
+func NewEchoLogger(logger *slog.Logger, w io.Writer) *EchoLogger {
+	return &EchoLogger{logger: logger, output: w, level: log.DEBUG}
+}
+
+// Output returns the writer the logger's handler writes to
+func (l *EchoLogger) Output() io.Writer { return l.output }
+
+// SetOutput is ignored: the slog handler owns the output
+func (l *EchoLogger) SetOutput(io.Writer) {}
+
+// Prefix returns the logger prefix
+func (l *EchoLogger) Prefix() string { return l.prefix }
+
+// SetPrefix sets the logger prefix, logged as the component attribute
+func (l *EchoLogger) SetPrefix(p string) {
+	l.prefix = p
+	l.logger = l.logger.With("component", p)
+}
+
+// Level returns the level last set with SetLevel
+func (l *EchoLogger) Level() log.Lvl { return l.level }
+
+// SetLevel records v; LOG_LEVEL decides what is written
+func (l *EchoLogger) SetLevel(v log.Lvl) { l.level = v }
+
+// SetHeader is ignored: the slog handler formats every line
+func (l *EchoLogger) SetHeader(string) {}
+
+// Print logs at the info level
+func (l *EchoLogger) Print(i ...interface{}) { l.log(slog.LevelInfo, fmt.Sprint(i...)) }
+
+// Printf logs at the info level
+func (l *EchoLogger) Printf(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Printj logs j's fields at the info level
+func (l *EchoLogger) Printj(j log.JSON) { l.logj(slog.LevelInfo, j) }
+
+// Debug logs at the debug level
+func (l *EchoLogger) Debug(i ...interface{}) { l.log(slog.LevelDebug, fmt.Sprint(i...)) }
+
+// Debugf logs at the debug level
+func (l *EchoLogger) Debugf(format string, args ...interface{}) {
+	l.log(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Debugj logs j's fields at the debug level
+func (l *EchoLogger) Debugj(j log.JSON) { l.logj(slog.LevelDebug, j) }
+
+// Info logs at the info level
+func (l *EchoLogger) Info(i ...interface{}) { l.log(slog.LevelInfo, fmt.Sprint(i...)) }
+
+// Infof logs at the info level
+func (l *EchoLogger) Infof(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Infoj logs j's fields at the info level
+func (l *EchoLogger) Infoj(j log.JSON) { l.logj(slog.LevelInfo, j) }
+
+// Warn logs at the warn level
+func (l *EchoLogger) Warn(i ...interface{}) { l.log(slog.LevelWarn, fmt.Sprint(i...)) }
+
+// Warnf logs at the warn level
+func (l *EchoLogger) Warnf(format string, args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Warnj logs j's fields at the warn level
+func (l *EchoLogger) Warnj(j log.JSON) { l.logj(slog.LevelWarn, j) }
+
+// Error logs at the error level
+func (l *EchoLogger) Error(i ...interface{}) { l.log(slog.LevelError, fmt.Sprint(i...)) }
+
+// Errorf logs at the error level
+func (l *EchoLogger) Errorf(format string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// Errorj logs j's fields at the error level
+func (l *EchoLogger) Errorj(j log.JSON) { l.logj(slog.LevelError, j) }
+
+// Fatal logs at the error level and exits
+func (l *EchoLogger) Fatal(i ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprint(i...))
+	os.Exit(1)
+}
+
+// Fatalf logs at the error level and exits
+func (l *EchoLogger) Fatalf(format string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// Fatalj logs j's fields at the error level and exits
+func (l *EchoLogger) Fatalj(j log.JSON) {
+	l.logj(slog.LevelError, j)
+	os.Exit(1)
+}
+
+// Panic logs at the error level and panics
+func (l *EchoLogger) Panic(i ...interface{}) {
+	msg := fmt.Sprint(i...)
+	l.log(slog.LevelError, msg)
+	panic(msg)
+}
+
+// Panicf logs at the error level and panics
+func (l *EchoLogger) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	l.log(slog.LevelError, msg)
+	panic(msg)
+}
+
+// Panicj logs j's fields at the error level and panics
+func (l *EchoLogger) Panicj(j log.JSON) {
+	l.logj(slog.LevelError, j)
+	panic(j)
+}
+
+// log writes msg at level
+func (l *EchoLogger) log(level slog.Level, msg string) {
+	l.logger.Log(context.Background(), level, msg)
+}
+
+// logj logs the fields of j as attributes, with its message field, if
+// any, as the message
+func (l *EchoLogger) logj(level slog.Level, j log.JSON) {
+	msg, _ := j["message"].(string)
+	keys := make([]string, 0, len(j))
+	for key := range j {
+		if key != "message" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, key := range keys {
+		attrs[i] = slog.Any(key, j[key])
+	}
+	l.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
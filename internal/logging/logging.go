@@ -0,0 +1,37 @@
+// Package logging builds the application's log/slog logger and carries a
+// request-scoped logger in the request context.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/Damianko135/playground-go/internal/config"
+)
+
+// contextKey is the context key of the request-scoped logger
+type contextKey struct{}
+
+// New creates a logger writing JSON or text lines to w, as configured
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: cfg.Level}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger
+// outside a request
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
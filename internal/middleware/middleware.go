@@ -2,18 +2,117 @@ package middleware
 
 import (
 	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"github.com/Damianko135/playground-go/internal/logging"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// CustomLogger returns a custom logger middleware
-func CustomLogger() echo.MiddlewareFunc {
-	return middleware.LoggerWithConfig(middleware.LoggerConfig{
-		Format:           "${time_custom} | ${status} | ${latency_human} | ${method} ${uri}\n",
-		CustomTimeFormat: "2006-01-02 15:04:05",
-		Output:           nil, // Use default output
+// tracer creates the server spans of incoming requests
+var tracer = otel.Tracer("github.com/Damianko135/playground-go/internal/middleware")
+
+// RequestLogger writes an access log line for each request and gives
+// handlers a logger carrying the request ID, route and trace ID through
+// logging.FromContext. Server errors are logged at the error level and
+// client errors at the warn level.
+func RequestLogger(logger *slog.Logger) echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogLatency:      true,
+		LogRemoteIP:     true,
+		LogMethod:       true,
+		LogURI:          true,
+		LogStatus:       true,
+		LogError:        true,
+		LogResponseSize: true,
+		LogUserAgent:    true,
+		// Write errors first, so the status logged is the one sent
+		HandleError: true,
+		BeforeNextFunc: func(c echo.Context) {
+			req := c.Request()
+			reqLogger := logger.With(requestAttrs(c)...)
+			c.SetRequest(req.WithContext(logging.WithLogger(req.Context(), reqLogger)))
+		},
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case v.Status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.Int("status", v.Status),
+				slog.Float64("latency_ms", float64(v.Latency.Microseconds())/1000),
+				slog.Int64("bytes", v.ResponseSize),
+				slog.String("remote_ip", v.RemoteIP),
+				slog.String("user_agent", v.UserAgent),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+
+			ctx := c.Request().Context()
+			logging.FromContext(ctx).LogAttrs(ctx, level, "request", attrs...)
+			return nil
+		},
+	})
+}
+
+// requestAttrs identify a request in every line logged while serving it
+func requestAttrs(c echo.Context) []any {
+	attrs := []any{slog.String("request_id", c.Response().Header().Get(echo.HeaderXRequestID))}
+	if route := c.Path(); route != "" && route != "/*" {
+		attrs = append(attrs, slog.String("route", route))
+	}
+	if span := trace.SpanContextFromContext(c.Request().Context()); span.IsValid() {
+		attrs = append(attrs,
+			slog.String("trace_id", span.TraceID().String()),
+			slog.String("span_id", span.SpanID().String()))
+	}
+	return attrs
+}
+
+// PanicError is a panic recovered while serving a request
+type PanicError struct {
+	Err   error
+	Stack []byte
+}
+
+// Error implements the error interface
+func (p *PanicError) Error() string {
+	return "panic: " + p.Err.Error()
+}
+
+// Unwrap returns the value the handler panicked with
+func (p *PanicError) Unwrap() error {
+	return p.Err
+}
+
+// Recover turns panics into a PanicError carrying the panicking
+// goroutine's stack. The error is returned like any handler error, so
+// middleware registered earlier sees it before the error handler reports it.
+func Recover() echo.MiddlewareFunc {
+	return middleware.RecoverWithConfig(middleware.RecoverConfig{
+		DisableStackAll:     true,
+		DisableErrorHandler: true,
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			ctx := c.Request().Context()
+			logging.FromContext(ctx).ErrorContext(ctx, "panic recovered",
+				slog.String("error", err.Error()), slog.String("stack", string(stack)))
+			return &PanicError{Err: err, Stack: stack}
+		},
 	})
 }
 
@@ -41,12 +140,59 @@ func RequestID() echo.MiddlewareFunc {
 	return middleware.RequestID()
 }
 
+// Tracing starts a server span for each request, continuing the caller's
+// trace when it sends a W3C traceparent header. Handlers find the span in the
+// request context. Errors are handled inside the span so it records the final
+// status; they are still returned to the middleware registered earlier.
+func Tracing() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			attrs := []attribute.KeyValue{
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.URLPath(req.URL.Path),
+				semconv.URLScheme(c.Scheme()),
+				semconv.ClientAddress(c.RealIP()),
+				semconv.UserAgentOriginal(req.UserAgent()),
+				attribute.String("http.request.id", c.Response().Header().Get(echo.HeaderXRequestID)),
+			}
+			name := req.Method
+			if route := c.Path(); route != "" && route != "/*" {
+				name += " " + route
+				attrs = append(attrs, semconv.HTTPRoute(route))
+			}
+
+			ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				span.RecordError(err)
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
+
 // CORS configures Cross-Origin Resource Sharing
 func CORS() echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization,
+			"traceparent", "tracestate"},
+		// Let browser clients see version and deprecation notices
+		ExposeHeaders: []string{"Deprecation", "Sunset", "Link"},
 	})
 }
 
@@ -111,6 +257,25 @@ func APIKeyAuth(apiKey string) echo.MiddlewareFunc {
 	}
 }
 
+// ModeratorAuth restricts moderation endpoints to requests carrying the
+// moderation key in the X-Moderator-Key header. Moderation is disabled when
+// no key is configured.
+func ModeratorAuth(moderationKey string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if moderationKey == "" {
+				return echo.NewHTTPError(403, "Moderation is disabled")
+			}
+
+			if c.Request().Header.Get("X-Moderator-Key") != moderationKey {
+				return echo.NewHTTPError(401, "Invalid moderator key")
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // isAPIEndpoint checks if the path is an API endpoint
 func isAPIEndpoint(path string) bool {
 	return len(path) >= 4 && path[:4] == "/api"
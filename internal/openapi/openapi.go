@@ -0,0 +1,304 @@
+// Package openapi generates an OpenAPI 3.1 description from documented API
+// routes. Schemas are derived from the json tags of the example request and
+// response values.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Damianko135/playground-go/internal/api"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.1.0"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Tags       []Tag                           `json:"tags,omitempty"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+	Security   []map[string][]string           `json:"security,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Operation is a single method on a path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a query or path parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response for a status code
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// SecurityScheme describes how requests authenticate
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// Builder collects routes into a document
+type Builder struct {
+	doc   Document
+	names map[reflect.Type]string
+
+	// ValidationError is an example of the body returned with 400 responses
+	// by routes that take parameters or a body
+	ValidationError interface{}
+	// Error is an example of the body returned with other error responses
+	Error interface{}
+	// ErrorMediaType is the content type of error responses, JSON by default
+	ErrorMediaType string
+}
+
+// New creates a builder for a document with the given info
+func New(info Info) *Builder {
+	return &Builder{
+		doc: Document{
+			OpenAPI:    Version,
+			Info:       info,
+			Paths:      map[string]map[string]Operation{},
+			Components: Components{Schemas: map[string]*Schema{}},
+		},
+		names: map[reflect.Type]string{},
+	}
+}
+
+// SecurityScheme adds a security scheme. Optional schemes are also listed
+// as alternatives at the document level, next to anonymous access.
+func (b *Builder) SecurityScheme(name string, scheme SecurityScheme, optional bool) {
+	if b.doc.Components.SecuritySchemes == nil {
+		b.doc.Components.SecuritySchemes = map[string]SecurityScheme{}
+	}
+	b.doc.Components.SecuritySchemes[name] = scheme
+
+	if optional {
+		if len(b.doc.Security) == 0 {
+			b.doc.Security = append(b.doc.Security, map[string][]string{})
+		}
+		b.doc.Security = append(b.doc.Security, map[string][]string{name: {}})
+	}
+}
+
+// pathParam matches echo path parameters such as :id
+var pathParam = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Add documents routes served under prefix
+func (b *Builder) Add(prefix string, routes []api.Route) {
+	for _, r := range routes {
+		path := pathParam.ReplaceAllString(prefix+r.Path, "{$1}")
+		if b.doc.Paths[path] == nil {
+			b.doc.Paths[path] = map[string]Operation{}
+		}
+		b.doc.Paths[path][strings.ToLower(r.Method)] = b.operation(r)
+		b.tag(tagName(r.Path))
+	}
+}
+
+// Document returns the document built so far
+func (b *Builder) Document() Document {
+	return b.doc
+}
+
+// operation documents a single route
+func (b *Builder) operation(r api.Route) Operation {
+	op := Operation{
+		OperationID: operationID(r),
+		Summary:     r.Summary,
+		Description: r.Description,
+		Tags:        []string{tagName(r.Path)},
+		Responses:   map[string]Response{},
+		Deprecated:  r.Deprecation != nil,
+	}
+
+	documented := map[string]bool{}
+	for _, p := range r.Params {
+		documented[p.Name] = true
+		schema := &Schema{Type: p.Type, Enum: p.Enum}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name: p.Name, In: p.In, Description: p.Description, Required: p.Required, Schema: schema,
+		})
+	}
+	for _, match := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+		if !documented[match[1]] {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"},
+			})
+		}
+	}
+
+	if r.Body != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: b.content(jsonMediaType, r.Body)}
+	}
+
+	success := Response{Description: http.StatusText(r.Status)}
+	if r.Response != nil {
+		success.Content = b.content(jsonMediaType, r.Response, r.OneOf...)
+	}
+	op.Responses[strconv.Itoa(r.Status)] = success
+
+	if b.ValidationError != nil && (len(op.Parameters) > 0 || r.Body != nil) {
+		op.Responses["400"] = Response{Description: "Invalid parameters", Content: b.content(b.errorMediaType(), b.ValidationError)}
+	}
+	if b.Error != nil {
+		op.Responses["default"] = Response{Description: "Error", Content: b.content(b.errorMediaType(), b.Error)}
+	}
+
+	op.Security = b.security(r.Security)
+
+	return op
+}
+
+// security combines a route's schemes with each alternative of the
+// document's security, so a route requiring its own key still accepts the
+// credentials every route takes. Routes without schemes inherit the
+// document's security.
+func (b *Builder) security(schemes []string) []map[string][]string {
+	if len(schemes) == 0 {
+		return nil
+	}
+	alternatives := b.doc.Security
+	if len(alternatives) == 0 {
+		alternatives = []map[string][]string{{}}
+	}
+
+	var security []map[string][]string
+	for _, alternative := range alternatives {
+		requirement := map[string][]string{}
+		for name, scopes := range alternative {
+			requirement[name] = scopes
+		}
+		for _, scheme := range schemes {
+			requirement[scheme] = []string{}
+		}
+		security = append(security, requirement)
+	}
+	return security
+}
+
+// jsonMediaType is the content type of request and response bodies
+const jsonMediaType = "application/json"
+
+// content returns a body of the given media type with the schema of
+// example, or one of the schemas of example and alternatives
+func (b *Builder) content(mediaType string, example interface{}, alternatives ...interface{}) map[string]MediaType {
+	schema := b.Schema(reflect.TypeOf(example))
+	if len(alternatives) > 0 {
+		schema = &Schema{OneOf: []*Schema{schema}}
+		for _, alternative := range alternatives {
+			schema.OneOf = append(schema.OneOf, b.Schema(reflect.TypeOf(alternative)))
+		}
+	}
+	return map[string]MediaType{mediaType: {Schema: schema}}
+}
+
+// errorMediaType returns the content type of error responses
+func (b *Builder) errorMediaType() string {
+	if b.ErrorMediaType == "" {
+		return jsonMediaType
+	}
+	return b.ErrorMediaType
+}
+
+// tag records a tag the first time it is used
+func (b *Builder) tag(name string) {
+	for _, t := range b.doc.Tags {
+		if t.Name == name {
+			return
+		}
+	}
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name})
+}
+
+// tagName groups routes by their first path segment
+func tagName(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return segment
+}
+
+// closureSuffix matches the suffix of closures and method values, such as
+// the .func1 of a handler returned by a constructor
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$|-fm$`)
+
+// operationID is the handler's function name in lower camel case. Handlers
+// built by a function are named after it.
+func operationID(r api.Route) string {
+	name := runtime.FuncForPC(reflect.ValueOf(r.Handler).Pointer()).Name()
+	name = closureSuffix.ReplaceAllString(name, "")
+	name = name[strings.LastIndex(name, ".")+1:]
+	if name == "" || strings.HasPrefix(name, "func") {
+		return strings.ToLower(r.Method) + strings.ReplaceAll(r.Path, "/", "_")
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
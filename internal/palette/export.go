@@ -0,0 +1,229 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// Export formats supported by Render
+const (
+	FormatCSS      = "css"
+	FormatSCSS     = "scss"
+	FormatTailwind = "tailwind"
+	FormatJSON     = "json"
+	FormatGPL      = "gpl"
+	FormatASE      = "ase"
+)
+
+// Formats lists every export format
+var Formats = []string{FormatCSS, FormatSCSS, FormatTailwind, FormatJSON, FormatGPL, FormatASE}
+
+// exportFormat describes how a format is served
+type exportFormat struct {
+	contentType string
+	extension   string
+	render      func(name string, colors []RGB) ([]byte, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	FormatCSS:      {"text/css; charset=utf-8", "css", renderCSS},
+	FormatSCSS:     {"text/x-scss; charset=utf-8", "scss", renderSCSS},
+	FormatTailwind: {"text/javascript; charset=utf-8", "tailwind.js", renderTailwind},
+	FormatJSON:     {"application/json; charset=utf-8", "json", renderJSON},
+	FormatGPL:      {"text/plain; charset=utf-8", "gpl", renderGPL},
+	FormatASE:      {"application/octet-stream", "ase", renderASE},
+}
+
+// ValidFormat reports whether format is a supported export format
+func ValidFormat(format string) bool {
+	_, ok := exportFormats[format]
+	return ok
+}
+
+// Export is a rendered palette file
+type Export struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Render renders the named palette in the given format
+func Render(format, name string, colors []RGB) (Export, error) {
+	f, ok := exportFormats[format]
+	if !ok {
+		return Export{}, fmt.Errorf("unknown export format %q", format)
+	}
+
+	data, err := f.render(name, colors)
+	if err != nil {
+		return Export{}, err
+	}
+
+	return Export{
+		Filename:    Slug(name) + "." + f.extension,
+		ContentType: f.contentType,
+		Data:        data,
+	}, nil
+}
+
+// Slug turns a palette name into a lowercase identifier safe for CSS
+// variables, file names and object keys
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	switch {
+	case slug == "":
+		return "palette"
+	case slug[0] >= '0' && slug[0] <= '9':
+		return "palette-" + slug
+	}
+	return slug
+}
+
+// renderCSS renders the palette as CSS custom properties
+func renderCSS(name string, colors []RGB) ([]byte, error) {
+	slug := Slug(name)
+	var b strings.Builder
+	fmt.Fprintf(&b, "/* %s */\n:root {\n", comment(name))
+	for i, c := range colors {
+		fmt.Fprintf(&b, "  --%s-%d: %s;\n", slug, i+1, c.Hex())
+	}
+	b.WriteString("}\n")
+	return []byte(b.String()), nil
+}
+
+// renderSCSS renders the palette as SCSS variables and a map
+func renderSCSS(name string, colors []RGB) ([]byte, error) {
+	slug := Slug(name)
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n", comment(name))
+	for i, c := range colors {
+		fmt.Fprintf(&b, "$%s-%d: %s;\n", slug, i+1, c.Hex())
+	}
+	fmt.Fprintf(&b, "\n$%s: (\n", slug)
+	for i := range colors {
+		fmt.Fprintf(&b, "  %d: $%s-%d,\n", i+1, slug, i+1)
+	}
+	b.WriteString(");\n")
+	return []byte(b.String()), nil
+}
+
+// renderTailwind renders the palette as a Tailwind config fragment that
+// extends the theme, so it can be merged into tailwind.config.js
+func renderTailwind(name string, colors []RGB) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n", comment(name))
+	b.WriteString("/** @type {import('tailwindcss').Config} */\n")
+	b.WriteString("module.exports = {\n  theme: {\n    extend: {\n      colors: {\n")
+	fmt.Fprintf(&b, "        '%s': {\n", Slug(name))
+	for i, c := range colors {
+		fmt.Fprintf(&b, "          %d: '%s',\n", i+1, c.Hex())
+	}
+	b.WriteString("        },\n      },\n    },\n  },\n}\n")
+	return []byte(b.String()), nil
+}
+
+// renderJSON renders the palette as a JSON document
+func renderJSON(name string, colors []RGB) ([]byte, error) {
+	doc := struct {
+		Name   string   `json:"name"`
+		Colors []string `json:"colors"`
+	}{Name: name, Colors: make([]string, len(colors))}
+	for i, c := range colors {
+		doc.Colors[i] = c.Hex()
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// renderGPL renders the palette as a GIMP/Inkscape palette
+func renderGPL(name string, colors []RGB) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "GIMP Palette\nName: %s\nColumns: %d\n#\n", comment(name), len(colors))
+	for _, c := range colors {
+		fmt.Fprintf(&b, "%3d %3d %3d\t%s\n", c.R, c.G, c.B, c.Hex())
+	}
+	return []byte(b.String()), nil
+}
+
+// ASE block types
+const (
+	aseGroupStart  uint16 = 0xc001
+	aseGroupEnd    uint16 = 0xc002
+	aseColorEntry  uint16 = 0x0001
+	aseColorGlobal        = 0
+)
+
+// renderASE renders the palette as an Adobe Swatch Exchange file, with the
+// colors in a group named after the palette
+func renderASE(name string, colors []RGB) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("ASEF")
+	write := func(v any) { _ = binary.Write(&buf, binary.BigEndian, v) }
+
+	write(uint16(1)) // major version
+	write(uint16(0)) // minor version
+	write(uint32(len(colors) + 2))
+
+	block := func(kind uint16, body []byte) {
+		write(kind)
+		write(uint32(len(body)))
+		buf.Write(body)
+	}
+
+	block(aseGroupStart, aseName(name))
+	for _, c := range colors {
+		var body bytes.Buffer
+		body.Write(aseName(c.Hex()))
+		body.WriteString("RGB ")
+		for _, v := range []uint8{c.R, c.G, c.B} {
+			_ = binary.Write(&body, binary.BigEndian, float32(v)/255)
+		}
+		_ = binary.Write(&body, binary.BigEndian, uint16(aseColorGlobal))
+		block(aseColorEntry, body.Bytes())
+	}
+	block(aseGroupEnd, nil)
+
+	return buf.Bytes(), nil
+}
+
+// aseName encodes a block name as a length-prefixed, null-terminated
+// UTF-16BE string
+func aseName(name string) []byte {
+	units := append(utf16.Encode([]rune(name)), 0)
+	if len(units) > math.MaxUint16 {
+		units = append(units[:math.MaxUint16-1], 0)
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(units)))
+	_ = binary.Write(&buf, binary.BigEndian, units)
+	return buf.Bytes()
+}
+
+// comment strips line breaks so a name can be embedded in a comment or header
+func comment(name string) string {
+	name = strings.NewReplacer("\r", " ", "\n", " ", "*/", "* /").Replace(name)
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,265 @@
+// Package palette generates color palettes from a base color using color
+// harmony rules
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Harmony modes supported by Generate
+const (
+	Complementary = "complementary"
+	Analogous     = "analogous"
+	Triadic       = "triadic"
+	Tetradic      = "tetradic"
+	Monochromatic = "monochromatic"
+)
+
+// Modes lists every harmony mode
+var Modes = []string{Complementary, Analogous, Triadic, Tetradic, Monochromatic}
+
+// ErrInvalidHex is returned for strings that are not #rgb or #rrggbb colors
+var ErrInvalidHex = errors.New("color must be a hex value like #3b82f6 or #38f")
+
+// RGB is a color in 8-bit sRGB
+type RGB struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// HSL is a color as hue in degrees and saturation/lightness in percent
+type HSL struct {
+	H float64 `json:"h"`
+	S float64 `json:"s"`
+	L float64 `json:"l"`
+}
+
+// Color is a palette entry with its representations and WCAG contrast
+// ratios against white and black text
+type Color struct {
+	Hex           string  `json:"hex"`
+	RGB           RGB     `json:"rgb"`
+	HSL           HSL     `json:"hsl"`
+	ContrastWhite float64 `json:"contrast_white"`
+	ContrastBlack float64 `json:"contrast_black"`
+	TextColor     string  `json:"text_color"`
+}
+
+// ParseHex parses a #rgb or #rrggbb color; the leading # is optional
+func ParseHex(s string) (RGB, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return RGB{}, ErrInvalidHex
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, ErrInvalidHex
+	}
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+// Hex returns the color as #rrggbb
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// HSL converts the color to hue, saturation and lightness
+func (c RGB) HSL() HSL {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	l := (maxC + minC) / 2
+
+	if maxC == minC {
+		return HSL{H: 0, S: 0, L: l * 100}
+	}
+
+	d := maxC - minC
+	s := d / (1 - math.Abs(2*l-1))
+
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+
+	return HSL{H: h, S: s * 100, L: l * 100}
+}
+
+// RGB converts the color to 8-bit sRGB
+func (c HSL) RGB() RGB {
+	h := math.Mod(math.Mod(c.H, 360)+360, 360)
+	s := clamp(c.S, 0, 100) / 100
+	l := clamp(c.L, 0, 100) / 100
+
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return RGB{
+		R: uint8(math.Round((r + m) * 255)),
+		G: uint8(math.Round((g + m) * 255)),
+		B: uint8(math.Round((b + m) * 255)),
+	}
+}
+
+// Luminance returns the WCAG relative luminance of the color
+func (c RGB) Luminance() float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colors
+func ContrastRatio(a, b RGB) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// NewColor describes an sRGB color with its contrast information
+func NewColor(c RGB) Color {
+	white := ContrastRatio(c, RGB{R: 255, G: 255, B: 255})
+	black := ContrastRatio(c, RGB{})
+
+	text := "#ffffff"
+	if black > white {
+		text = "#000000"
+	}
+
+	hsl := c.HSL()
+	return Color{
+		Hex:           c.Hex(),
+		RGB:           c,
+		HSL:           HSL{H: round(hsl.H, 1), S: round(hsl.S, 1), L: round(hsl.L, 1)},
+		ContrastWhite: round(white, 2),
+		ContrastBlack: round(black, 2),
+		TextColor:     text,
+	}
+}
+
+// ValidMode reports whether mode is a supported harmony mode
+func ValidMode(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate returns count colors harmonizing with base. The base color is
+// always the first entry. When the mode has fewer hues than count, further
+// colors cycle through the hues as lighter and darker variants. Grays have no
+// hue to rotate and always get a monochromatic palette.
+func Generate(base RGB, mode string, count int) ([]Color, error) {
+	if !ValidMode(mode) {
+		return nil, fmt.Errorf("unknown harmony mode %q", mode)
+	}
+	if count < 1 {
+		return nil, errors.New("count must be at least 1")
+	}
+
+	hsl := base.HSL()
+	if hsl.S < 1 {
+		// Rotating the hue of a gray does nothing, so vary lightness instead
+		mode = Monochromatic
+	}
+
+	colors := make([]Color, 0, count)
+	colors = append(colors, NewColor(base))
+
+	for i := 1; i < count; i++ {
+		colors = append(colors, NewColor(harmonize(hsl, mode, i, count).RGB()))
+	}
+	return colors, nil
+}
+
+// harmonize returns the i-th color of a palette of count colors
+func harmonize(base HSL, mode string, i, count int) HSL {
+	switch mode {
+	case Monochromatic:
+		// Spread lightness evenly, wrapping around the base lightness
+		step := 70.0 / float64(count)
+		return HSL{H: base.H, S: base.S, L: 15 + math.Mod(base.L-15+float64(i)*step, 70)}
+	case Analogous:
+		// Alternate neighbours at increasing 30° steps on either side
+		offset := float64((i+1)/2) * 30
+		if i%2 == 0 {
+			offset = -offset
+		}
+		return HSL{H: base.H + offset, S: base.S, L: base.L}
+	}
+
+	var offsets []float64
+	switch mode {
+	case Complementary:
+		offsets = []float64{0, 180}
+	case Triadic:
+		offsets = []float64{0, 120, 240}
+	default: // Tetradic
+		offsets = []float64{0, 90, 180, 270}
+	}
+
+	// Later rounds alternate between lighter and darker variants
+	cycle := i / len(offsets)
+	shift := float64((cycle+1)/2) * 15
+	if cycle%2 == 0 {
+		shift = -shift
+	}
+
+	return HSL{
+		H: base.H + offsets[i%len(offsets)],
+		S: base.S,
+		L: clamp(base.L+shift, 5, 95),
+	}
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,100 @@
+package random
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Limits for dice notation
+const (
+	MaxDice     = 100
+	MaxSides    = 1000
+	MaxModifier = 1000000
+)
+
+// dicePattern matches notation such as d20, 2d6, 4d8+3 and 1d10-1
+var dicePattern = regexp.MustCompile(`^(\d*)d(\d+)(?:([+-])(\d+))?$`)
+
+// ErrInvalidDice is returned for malformed dice notation
+var ErrInvalidDice = errors.New("must be dice notation like 2d6, d20 or 3d8+2")
+
+// Dice describes a roll in NdS+M notation
+type Dice struct {
+	Count    int
+	Sides    int
+	Modifier int
+}
+
+// DiceRoll is the outcome of rolling dice
+type DiceRoll struct {
+	Dice  []int `json:"dice"`
+	Total int   `json:"total"`
+}
+
+// ParseDice parses dice notation such as 2d6+3. The number of dice defaults
+// to one.
+func ParseDice(notation string) (Dice, error) {
+	m := dicePattern.FindStringSubmatch(compactDice(notation))
+	if m == nil {
+		return Dice{}, ErrInvalidDice
+	}
+
+	d := Dice{Count: 1}
+	var err error
+	if m[1] != "" {
+		if d.Count, err = strconv.Atoi(m[1]); err != nil || d.Count < 1 || d.Count > MaxDice {
+			return Dice{}, fmt.Errorf("must roll between 1 and %d dice", MaxDice)
+		}
+	}
+	if d.Sides, err = strconv.Atoi(m[2]); err != nil || d.Sides < 2 || d.Sides > MaxSides {
+		return Dice{}, fmt.Errorf("dice must have between 2 and %d sides", MaxSides)
+	}
+	if m[4] != "" {
+		if d.Modifier, err = strconv.Atoi(m[4]); err != nil || d.Modifier > MaxModifier {
+			return Dice{}, fmt.Errorf("modifier must be at most %d", MaxModifier)
+		}
+		if m[3] == "-" {
+			d.Modifier = -d.Modifier
+		}
+	}
+	return d, nil
+}
+
+// compactDice lowercases notation and removes whitespace. A '+' sent
+// unescaped in a query string arrives as a space, so a space between two
+// terms that has no sign next to it is read as '+'.
+func compactDice(notation string) string {
+	var b strings.Builder
+	for i, field := range strings.Fields(strings.ToLower(notation)) {
+		if i > 0 && !strings.HasSuffix(b.String(), "+") && !strings.HasSuffix(b.String(), "-") &&
+			!strings.HasPrefix(field, "+") && !strings.HasPrefix(field, "-") {
+			b.WriteByte('+')
+		}
+		b.WriteString(field)
+	}
+	return b.String()
+}
+
+// String returns the dice in canonical notation
+func (d Dice) String() string {
+	switch {
+	case d.Modifier > 0:
+		return fmt.Sprintf("%dd%d+%d", d.Count, d.Sides, d.Modifier)
+	case d.Modifier < 0:
+		return fmt.Sprintf("%dd%d%d", d.Count, d.Sides, d.Modifier)
+	}
+	return fmt.Sprintf("%dd%d", d.Count, d.Sides)
+}
+
+// Roll rolls the dice and adds the modifier to the total
+func (d Dice) Roll() DiceRoll {
+	roll := DiceRoll{Dice: make([]int, d.Count), Total: d.Modifier}
+	for i := range roll.Dice {
+		roll.Dice[i] = int(Int(1, int64(d.Sides)))
+		roll.Total += roll.Dice[i]
+	}
+	return roll
+}
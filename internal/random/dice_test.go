@@ -0,0 +1,96 @@
+package random
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDice(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     Dice
+		canon    string
+	}{
+		{notation: "d20", want: Dice{Count: 1, Sides: 20}, canon: "1d20"},
+		{notation: "2d6", want: Dice{Count: 2, Sides: 6}, canon: "2d6"},
+		{notation: "3d8+2", want: Dice{Count: 3, Sides: 8, Modifier: 2}, canon: "3d8+2"},
+		{notation: "1d10-1", want: Dice{Count: 1, Sides: 10, Modifier: -1}, canon: "1d10-1"},
+		{notation: " 4D6 ", want: Dice{Count: 4, Sides: 6}, canon: "4d6"},
+		{notation: "2d6 + 3", want: Dice{Count: 2, Sides: 6, Modifier: 3}, canon: "2d6+3"},
+		{notation: "2d6 3", want: Dice{Count: 2, Sides: 6, Modifier: 3}, canon: "2d6+3"},
+		{notation: "2d6 -3", want: Dice{Count: 2, Sides: 6, Modifier: -3}, canon: "2d6-3"},
+		{notation: "100d1000+1000000", want: Dice{Count: MaxDice, Sides: MaxSides, Modifier: MaxModifier}, canon: "100d1000+1000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := ParseDice(tt.notation)
+			if err != nil {
+				t.Fatalf("ParseDice(%q): %v", tt.notation, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDice(%q) = %+v, want %+v", tt.notation, got, tt.want)
+			}
+			if s := got.String(); s != tt.canon {
+				t.Errorf("String() = %q, want %q", s, tt.canon)
+			}
+		})
+	}
+}
+
+func TestParseDiceInvalid(t *testing.T) {
+	tests := []struct {
+		notation  string
+		malformed bool
+	}{
+		{notation: "", malformed: true},
+		{notation: "d", malformed: true},
+		{notation: "2d", malformed: true},
+		{notation: "6", malformed: true},
+		{notation: "2x6", malformed: true},
+		{notation: "2d6+", malformed: true},
+		{notation: "2d6+1+1", malformed: true},
+		{notation: "-2d6", malformed: true},
+		{notation: "2d6*2", malformed: true},
+		{notation: "0d6"},
+		{notation: "101d6"},
+		{notation: "2d1"},
+		{notation: "2d0"},
+		{notation: "2d1001"},
+		{notation: "2d6+1000001"},
+		{notation: "2d6-1000001"},
+		{notation: "99999999999999999999d6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			_, err := ParseDice(tt.notation)
+			if err == nil {
+				t.Fatalf("ParseDice(%q) succeeded, want an error", tt.notation)
+			}
+			if malformed := errors.Is(err, ErrInvalidDice); malformed != tt.malformed {
+				t.Errorf("ParseDice(%q) = %v, malformed = %v, want %v", tt.notation, err, malformed, tt.malformed)
+			}
+		})
+	}
+}
+
+func TestDiceRoll(t *testing.T) {
+	d := Dice{Count: 5, Sides: 6, Modifier: -2}
+	for i := 0; i < 200; i++ {
+		roll := d.Roll()
+		if len(roll.Dice) != d.Count {
+			t.Fatalf("rolled %d dice, want %d", len(roll.Dice), d.Count)
+		}
+		total := d.Modifier
+		for _, die := range roll.Dice {
+			if die < 1 || die > d.Sides {
+				t.Fatalf("die = %d, want 1-%d", die, d.Sides)
+			}
+			total += die
+		}
+		if roll.Total != total {
+			t.Fatalf("Total = %d, want %d for %v", roll.Total, total, roll.Dice)
+		}
+	}
+}
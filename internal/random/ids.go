@@ -0,0 +1,135 @@
+package random
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// Identifier kinds supported by ID
+const (
+	KindUUID4  = "uuid4"
+	KindUUID7  = "uuid7"
+	KindULID   = "ulid"
+	KindNanoID = "nanoid"
+)
+
+// IDKinds lists every identifier kind
+var IDKinds = []string{KindUUID4, KindUUID7, KindULID, KindNanoID}
+
+// NanoIDAlphabet is the URL-safe alphabet used by NanoID
+const NanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// DefaultNanoIDSize is the standard NanoID length, about as collision
+// resistant as a UUID v4
+const DefaultNanoIDSize = 21
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ValidIDKind reports whether kind is a supported identifier kind
+func ValidIDKind(kind string) bool {
+	for _, k := range IDKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ID returns a new identifier of the given kind. NanoIDs use the default
+// size and alphabet. It panics on an unknown kind.
+func ID(kind string) string {
+	switch kind {
+	case KindUUID4:
+		return UUIDv4()
+	case KindUUID7:
+		return UUIDv7()
+	case KindULID:
+		return ULID()
+	case KindNanoID:
+		return NanoID(DefaultNanoIDSize)
+	}
+	panic("random: unknown identifier kind " + kind)
+}
+
+// UUIDv4 returns a random RFC 9562 version 4 UUID
+func UUIDv4() string {
+	var u [16]byte
+	fill(u[:])
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 9562 variant
+	return formatUUID(u)
+}
+
+// UUIDv7 returns an RFC 9562 version 7 UUID: a millisecond Unix timestamp
+// followed by random bits, so IDs sort roughly by creation time
+func UUIDv7() string {
+	var u [16]byte
+	fill(u[6:])
+	putMillis(u[:6], time.Now())
+	u[6] = u[6]&0x0f | 0x70 // version 7
+	u[8] = u[8]&0x3f | 0x80 // RFC 9562 variant
+	return formatUUID(u)
+}
+
+// putMillis writes the Unix time of t in milliseconds as a 48-bit big-endian
+// integer
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := range 6 {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+}
+
+// formatUUID renders a UUID in its canonical hyphenated form
+func formatUUID(u [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// ULID returns a Universally Unique Lexicographically Sortable Identifier: a
+// 48-bit millisecond timestamp and 80 random bits in Crockford base32
+func ULID() string {
+	var u [16]byte
+	fill(u[6:])
+	putMillis(u[:6], time.Now())
+	return formatULID(u)
+}
+
+// formatULID renders 128 bits in Crockford base32
+func formatULID(u [16]byte) string {
+	// 128 bits encode to 26 characters of 5 bits, least significant last
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// NanoID returns a NanoID of the given size using NanoIDAlphabet
+func NanoID(size int) string {
+	return String(NanoIDAlphabet, size)
+}
+
+// String returns n characters drawn uniformly from alphabet
+func String(alphabet string, n int) string {
+	runes := []rune(alphabet)
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = runes[Int(0, int64(len(runes)-1))]
+	}
+	return string(out)
+}
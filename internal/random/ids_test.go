@@ -0,0 +1,166 @@
+package random
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatULID(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		want string
+	}{
+		{name: "zero", hex: "00000000000000000000000000000000", want: "00000000000000000000000000"},
+		{name: "max", hex: "ffffffffffffffffffffffffffffffff", want: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{name: "lowest bit", hex: "00000000000000000000000000000001", want: "00000000000000000000000001"},
+		{name: "highest bit", hex: "80000000000000000000000000000000", want: "40000000000000000000000000"},
+		// The timestamp of the example in the ULID specification
+		{name: "timestamp", hex: "01563df36481" + "00000000000000000000", want: "01ARYZ6S41" + "0000000000000000"},
+		{name: "bits across words", hex: "0000000000000001" + "8000000000000000", want: "0000000000000R000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u [16]byte
+			if _, err := hex.Decode(u[:], []byte(tt.hex)); err != nil {
+				t.Fatal(err)
+			}
+			if got := formatULID(u); got != tt.want {
+				t.Errorf("formatULID(%s) = %s, want %s", tt.hex, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPutMillis(t *testing.T) {
+	var b [6]byte
+	putMillis(b[:], time.UnixMilli(1469918176385))
+	if got := hex.EncodeToString(b[:]); got != "01563df36481" {
+		t.Errorf("putMillis = %s, want 01563df36481", got)
+	}
+}
+
+// ulidMillis decodes the timestamp in the first ten characters of a ULID
+func ulidMillis(t *testing.T, id string) int64 {
+	t.Helper()
+	var ms int64
+	for _, c := range id[:10] {
+		i := strings.IndexRune(crockford, c)
+		if i < 0 {
+			t.Fatalf("ULID %s has a character outside the Crockford alphabet", id)
+		}
+		ms = ms<<5 | int64(i)
+	}
+	return ms
+}
+
+// uuidBytes parses a hyphenated UUID
+func uuidBytes(t *testing.T, id string) [16]byte {
+	t.Helper()
+	var u [16]byte
+	if len(id) != 36 || id[8] != '-' || id[13] != '-' || id[18] != '-' || id[23] != '-' {
+		t.Fatalf("UUID %s is not in hyphenated form", id)
+	}
+	if _, err := hex.Decode(u[:], []byte(strings.ReplaceAll(id, "-", ""))); err != nil {
+		t.Fatalf("UUID %s: %v", id, err)
+	}
+	return u
+}
+
+func TestULID(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id := ULID()
+	after := time.Now().UnixMilli()
+
+	if len(id) != 26 {
+		t.Fatalf("ULID %s has %d characters, want 26", id, len(id))
+	}
+	if id[0] > '7' {
+		t.Errorf("ULID %s starts with %c, beyond 128 bits", id, id[0])
+	}
+	for _, c := range id {
+		if !strings.ContainsRune(crockford, c) {
+			t.Fatalf("ULID %s has %c, outside the Crockford alphabet", id, c)
+		}
+	}
+	if ms := ulidMillis(t, id); ms < before || ms > after {
+		t.Errorf("ULID %s has timestamp %d, want between %d and %d", id, ms, before, after)
+	}
+}
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id := UUIDv7()
+	after := time.Now().UnixMilli()
+
+	u := uuidBytes(t, id)
+	if version := u[6] >> 4; version != 7 {
+		t.Errorf("UUID %s has version %d, want 7", id, version)
+	}
+	if variant := u[8] >> 6; variant != 0b10 {
+		t.Errorf("UUID %s has variant bits %02b, want 10", id, variant)
+	}
+	var ms int64
+	for _, b := range u[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	if ms < before || ms > after {
+		t.Errorf("UUID %s has timestamp %d, want between %d and %d", id, ms, before, after)
+	}
+}
+
+func TestUUIDv4(t *testing.T) {
+	id := UUIDv4()
+	u := uuidBytes(t, id)
+	if version := u[6] >> 4; version != 4 {
+		t.Errorf("UUID %s has version %d, want 4", id, version)
+	}
+	if variant := u[8] >> 6; variant != 0b10 {
+		t.Errorf("UUID %s has variant bits %02b, want 10", id, variant)
+	}
+}
+
+func TestTimeOrderedIDsSort(t *testing.T) {
+	for _, kind := range []string{KindULID, KindUUID7} {
+		t.Run(kind, func(t *testing.T) {
+			// IDs from later milliseconds sort after earlier ones
+			previous := ID(kind)
+			for i := 0; i < 5; i++ {
+				time.Sleep(2 * time.Millisecond)
+				next := ID(kind)
+				if next <= previous {
+					t.Fatalf("%s %s sorts before the earlier %s", kind, next, previous)
+				}
+				previous = next
+			}
+		})
+	}
+}
+
+func TestNanoID(t *testing.T) {
+	for _, size := range []int{0, 1, DefaultNanoIDSize, 64} {
+		id := NanoID(size)
+		if len(id) != size {
+			t.Errorf("NanoID(%d) = %q, length %d", size, id, len(id))
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(NanoIDAlphabet, c) {
+				t.Errorf("NanoID(%d) = %q has %c, outside the alphabet", size, id, c)
+			}
+		}
+	}
+}
+
+func TestNanoIDUsesWholeAlphabet(t *testing.T) {
+	// 2,000 characters from 64 symbols miss one with negligible probability
+	seen := map[rune]bool{}
+	for _, c := range NanoID(2000) {
+		seen[c] = true
+	}
+	if len(seen) != len(NanoIDAlphabet) {
+		t.Errorf("NanoID drew %d distinct characters, want %d", len(seen), len(NanoIDAlphabet))
+	}
+}
@@ -0,0 +1,95 @@
+package random
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Character classes for passwords
+const (
+	ClassLower   = "lower"
+	ClassUpper   = "upper"
+	ClassDigits  = "digits"
+	ClassSymbols = "symbols"
+)
+
+// Classes lists every character class, in the order they are combined
+var Classes = []string{ClassLower, ClassUpper, ClassDigits, ClassSymbols}
+
+// classCharacters maps each class to its characters. Symbols are limited to
+// ones that survive shells, URLs and config files without quoting trouble.
+var classCharacters = map[string]string{
+	ClassLower:   "abcdefghijklmnopqrstuvwxyz",
+	ClassUpper:   "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	ClassDigits:  "0123456789",
+	ClassSymbols: "!#$%&*+-.:=?@^_~",
+}
+
+//go:embed wordlist.txt
+var wordlistText string
+
+// wordlist holds the words used for passphrases
+var wordlist = strings.Fields(wordlistText)
+
+// ValidClass reports whether class is a supported character class
+func ValidClass(class string) bool {
+	_, ok := classCharacters[class]
+	return ok
+}
+
+// Password returns a password of the given length that contains at least
+// one character from every requested class, and an estimate of its entropy
+// in bits
+func Password(length int, classes []string) (string, float64, error) {
+	if len(classes) == 0 {
+		return "", 0, fmt.Errorf("at least one character class is required")
+	}
+	if length < len(classes) {
+		return "", 0, fmt.Errorf("length must be at least %d to include every class", len(classes))
+	}
+
+	var pool string
+	out := make([]byte, 0, length)
+	for _, class := range Classes {
+		if !containsClass(classes, class) {
+			continue
+		}
+		chars := classCharacters[class]
+		pool += chars
+		out = append(out, String(chars, 1)...)
+	}
+	out = append(out, String(pool, length-len(out))...)
+
+	// Shuffle so the guaranteed characters are not always first
+	source.Shuffle(len(out), func(i, j int) {
+		out[i], out[j] = out[j], out[i]
+	})
+
+	return string(out), float64(length) * math.Log2(float64(len(pool))), nil
+}
+
+// containsClass reports whether classes contains class
+func containsClass(classes []string, class string) bool {
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Passphrase returns the given number of words from the embedded wordlist
+// joined by separator, and its entropy in bits
+func Passphrase(words int, separator string, capitalize bool) (string, float64) {
+	picked := make([]string, words)
+	for i := range picked {
+		word := wordlist[Int(0, int64(len(wordlist)-1))]
+		if capitalize {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		picked[i] = word
+	}
+	return strings.Join(picked, separator), float64(words) * math.Log2(float64(len(wordlist)))
+}
@@ -0,0 +1,62 @@
+// Package random draws numbers from a cryptographically secure source. All
+// functions are safe for concurrent use.
+package random
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+)
+
+// cryptoSource is a math/rand/v2 source backed by crypto/rand
+type cryptoSource struct{}
+
+// Uint64 returns 64 random bits from crypto/rand
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	fill(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// fill fills b with random bytes from crypto/rand
+func fill(b []byte) {
+	// crypto/rand.Read never returns an error on supported platforms
+	_, _ = cryptorand.Read(b)
+}
+
+// source wraps the crypto source with math/rand/v2's unbiased range and
+// distribution helpers. rand.Rand keeps no state of its own, so sharing it
+// is safe.
+var source = rand.New(cryptoSource{})
+
+// Int returns a uniformly distributed integer in [min, max]. It covers the
+// full int64 range without overflowing and panics if min > max.
+func Int(min, max int64) int64 {
+	if min > max {
+		panic("random: min greater than max")
+	}
+	// The span is computed in uint64 so max-min cannot overflow
+	span := uint64(max) - uint64(min)
+	if span == math.MaxUint64 {
+		return int64(source.Uint64())
+	}
+	return int64(uint64(min) + source.Uint64N(span+1))
+}
+
+// Float returns a uniformly distributed float in [min, max)
+func Float(min, max float64) float64 {
+	return min + source.Float64()*(max-min)
+}
+
+// Normal returns a normally distributed float with the given mean and
+// standard deviation
+func Normal(mean, stddev float64) float64 {
+	return mean + source.NormFloat64()*stddev
+}
+
+// Exponential returns an exponentially distributed float with the given rate
+// (lambda); its mean is 1/rate
+func Exponential(rate float64) float64 {
+	return source.ExpFloat64() / rate
+}
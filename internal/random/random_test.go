@@ -0,0 +1,59 @@
+package random
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int64
+	}{
+		{name: "single value", min: 7, max: 7},
+		{name: "small range", min: -3, max: 3},
+		{name: "below zero", min: -10, max: -5},
+		{name: "lowest values", min: math.MinInt64, max: math.MinInt64 + 4},
+		{name: "highest values", min: math.MaxInt64 - 4, max: math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every value of a small range turns up within a few hundred draws
+			seen := map[int64]bool{}
+			for i := 0; i < 500; i++ {
+				n := Int(tt.min, tt.max)
+				if n < tt.min || n > tt.max {
+					t.Fatalf("Int(%d, %d) = %d, out of range", tt.min, tt.max, n)
+				}
+				seen[n] = true
+			}
+			if want := int(tt.max - tt.min + 1); len(seen) != want {
+				t.Errorf("Int(%d, %d) drew %d distinct values, want %d", tt.min, tt.max, len(seen), want)
+			}
+		})
+	}
+}
+
+func TestIntFullRange(t *testing.T) {
+	// The span of the full range does not fit in uint64; both halves of the
+	// range should still come up
+	var negative, positive bool
+	for i := 0; i < 200; i++ {
+		n := Int(math.MinInt64, math.MaxInt64)
+		negative = negative || n < 0
+		positive = positive || n >= 0
+	}
+	if !negative || !positive {
+		t.Errorf("Int(MinInt64, MaxInt64) drew negative = %v, positive = %v, want both", negative, positive)
+	}
+}
+
+func TestIntPanicsWhenMinExceedsMax(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Int(2, 1) did not panic")
+		}
+	}()
+	Int(2, 1)
+}
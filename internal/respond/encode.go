@@ -0,0 +1,336 @@
+package respond
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// member is a key and value of a decoded JSON object
+type member struct {
+	Key   string
+	Value interface{}
+}
+
+// object is a decoded JSON object with its key order preserved, so that XML,
+// YAML, CSV and text list fields in the same order as the JSON response
+type object []member
+
+// Table is implemented by responses that wrap a list, such as a page of
+// results. Their CSV form has one row per element of the list under the JSON
+// key TableKey returns; other objects are written as a single row.
+type Table interface {
+	TableKey() string
+}
+
+// Encode writes data in the given format. Data is first marshalled to JSON,
+// so json struct tags decide field names in every format. HTML is not
+// handled here, it is rendered from templ fragments.
+func Encode(w io.Writer, format string, data interface{}) error {
+	if format == JSON {
+		return json.NewEncoder(w).Encode(data)
+	}
+
+	doc, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case XML:
+		return encodeXML(w, doc)
+	case YAML:
+		return encodeYAML(w, doc)
+	case CSV:
+		var key string
+		if table, ok := data.(Table); ok {
+			key = table.TableKey()
+		}
+		return encodeCSV(w, doc, key)
+	case Text:
+		return encodeText(w, doc)
+	}
+	return fmt.Errorf("respond: cannot encode %q", format)
+}
+
+// decode converts data to a tree of object, []interface{} and scalar values
+func decode(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+// decodeValue reads the next JSON value from dec
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{Key: key.(string), Value: value})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return token, nil
+}
+
+// scalarText formats a string, number, boolean or null as text
+func scalarText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// flatten calls emit for every scalar in value with its dotted path, using
+// indexes for list elements. Empty objects and lists are emitted as "".
+func flatten(path string, value interface{}, emit func(path, value string)) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch v := value.(type) {
+	case object:
+		if len(v) == 0 {
+			emit(path, "")
+		}
+		for _, m := range v {
+			flatten(join(m.Key), m.Value, emit)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			emit(path, "")
+		}
+		for i, item := range v {
+			flatten(join(strconv.Itoa(i)), item, emit)
+		}
+	default:
+		emit(path, scalarText(v))
+	}
+}
+
+// encodeText writes one "path: value" line per scalar, or just the value
+// when the document is a single scalar
+func encodeText(w io.Writer, doc interface{}) error {
+	var b strings.Builder
+	flatten("", doc, func(path, value string) {
+		if path != "" {
+			b.WriteString(path + ": ")
+		}
+		b.WriteString(value + "\n")
+	})
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// encodeCSV writes the document as a table. A list becomes one row per
+// element, as does the list under key in an object; any other object is one
+// row. Nested values, including lists, become dotted column names such as
+// tags.0.
+func encodeCSV(w io.Writer, doc interface{}, key string) error {
+	rows, name := tableRows(doc, key)
+
+	var columns []string
+	index := map[string]int{}
+	records := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		records[i] = map[string]string{}
+		prefix := ""
+		if _, ok := row.(object); !ok {
+			prefix = name
+		}
+		flatten(prefix, row, func(path, value string) {
+			if _, ok := index[path]; !ok {
+				index[path] = len(columns)
+				columns = append(columns, path)
+			}
+			records[i][path] = value
+		})
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = record[column]
+		}
+		if err := out.Write(line); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// tableRows picks the rows of a CSV table and the column name for rows that
+// are plain values. Key names the list of a Table response, if any.
+func tableRows(doc interface{}, key string) ([]interface{}, string) {
+	switch v := doc.(type) {
+	case []interface{}:
+		return v, "value"
+	case object:
+		if key == "" {
+			break
+		}
+		for _, m := range v {
+			if list, ok := m.Value.([]interface{}); ok && m.Key == key {
+				return list, key
+			}
+		}
+	}
+	return []interface{}{doc}, "value"
+}
+
+// encodeXML writes the document below a <response> root. Lists repeat an
+// <item> element for each value.
+func encodeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := xmlElement(enc, "response", doc); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// xmlElement writes value as an element called name
+func xmlElement(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case object:
+		for _, m := range v {
+			if err := xmlElement(enc, m.Key, m.Value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := xmlElement(enc, "item", item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := enc.EncodeToken(xml.CharData(scalarText(v))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlName turns a JSON key into a valid XML element name
+func xmlName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, key)
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) && name[0] != '_' {
+		name = "_" + name
+	}
+	return name
+}
+
+// encodeYAML writes the document as a YAML mapping, sequence or scalar
+func encodeYAML(w io.Writer, doc interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(doc)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// yamlNode converts a decoded value to a YAML node, tagging scalars so that
+// strings that look like numbers stay strings
+func yamlNode(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case object:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, m := range v {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.Key},
+				yamlNode(m.Value))
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: scalarText(value)}
+}
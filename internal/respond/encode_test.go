@@ -0,0 +1,84 @@
+package respond
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testQuote struct {
+	ID     int      `json:"id"`
+	Text   string   `json:"text"`
+	Author string   `json:"author"`
+	Tags   []string `json:"tags"`
+}
+
+type testQuoteList struct {
+	Quotes []testQuote `json:"quotes"`
+	Page   int         `json:"page"`
+}
+
+func (testQuoteList) TableKey() string { return "quotes" }
+
+type testNumbers struct {
+	Numbers []int `json:"numbers"`
+	Count   int   `json:"count"`
+}
+
+func (testNumbers) TableKey() string { return "numbers" }
+
+func TestEncodeCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{
+			name: "single resource is one row",
+			data: testQuote{ID: 1, Text: "Hello, world", Author: "Ada", Tags: []string{"motivation", "work"}},
+			want: "id,text,author,tags.0,tags.1\n" +
+				"1,\"Hello, world\",Ada,motivation,work\n",
+		},
+		{
+			name: "single resource with an empty list",
+			data: testQuote{ID: 2, Text: "Hi", Author: "Bob", Tags: []string{}},
+			want: "id,text,author,tags\n2,Hi,Bob,\n",
+		},
+		{
+			name: "unmarked wrapper is one row",
+			data: map[string]interface{}{"types": []string{"pun", "dad"}},
+			want: "types.0,types.1\npun,dad\n",
+		},
+		{
+			name: "table of objects",
+			data: testQuoteList{Page: 1, Quotes: []testQuote{
+				{ID: 1, Text: "A", Author: "Ada", Tags: []string{"x"}},
+				{ID: 2, Text: "B", Author: "Bob", Tags: []string{"y", "z"}},
+			}},
+			want: "id,text,author,tags.0,tags.1\n" +
+				"1,A,Ada,x,\n" +
+				"2,B,Bob,y,z\n",
+		},
+		{
+			name: "table of values",
+			data: testNumbers{Numbers: []int{4, 8, 15}, Count: 3},
+			want: "numbers\n4\n8\n15\n",
+		},
+		{
+			name: "top-level list",
+			data: []string{"a", "b"},
+			want: "value\na\nb\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, CSV, tt.data); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Encode CSV =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
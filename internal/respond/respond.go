@@ -0,0 +1,116 @@
+// Package respond negotiates response formats and encodes data as JSON, XML,
+// YAML, CSV or plain text.
+package respond
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response formats
+const (
+	JSON = "json"
+	HTML = "html"
+	XML  = "xml"
+	YAML = "yaml"
+	CSV  = "csv"
+	Text = "text"
+)
+
+// Formats lists the supported formats in order of preference
+var Formats = []string{JSON, HTML, XML, YAML, CSV, Text}
+
+// ErrNotAcceptable is returned when the Accept header matches no format
+var ErrNotAcceptable = errors.New("no acceptable response format")
+
+// ContentTypes maps each format to the Content-Type it is served with
+var ContentTypes = map[string]string{
+	JSON: "application/json; charset=UTF-8",
+	HTML: "text/html; charset=UTF-8",
+	XML:  "application/xml; charset=UTF-8",
+	YAML: "application/yaml; charset=UTF-8",
+	CSV:  "text/csv; charset=UTF-8",
+	Text: "text/plain; charset=UTF-8",
+}
+
+// mediaTypes maps Accept header media types to formats
+var mediaTypes = map[string]string{
+	"application/json":   JSON,
+	"text/json":          JSON,
+	"application/*":      JSON,
+	"*/*":                JSON,
+	"text/html":          HTML,
+	"application/xml":    XML,
+	"text/xml":           XML,
+	"application/yaml":   YAML,
+	"application/x-yaml": YAML,
+	"text/yaml":          YAML,
+	"text/csv":           CSV,
+	"text/plain":         Text,
+	"text/*":             Text,
+}
+
+// Valid reports whether format is a supported format
+func Valid(format string) bool {
+	_, ok := ContentTypes[format]
+	return ok
+}
+
+// Available returns the formats a handler can serve; HTML needs a fragment
+func Available(html bool) []string {
+	if html {
+		return Formats
+	}
+	available := make([]string, 0, len(Formats)-1)
+	for _, format := range Formats {
+		if format != HTML {
+			available = append(available, format)
+		}
+	}
+	return available
+}
+
+// Negotiate picks the format with the highest quality in the Accept header,
+// preferring earlier entries on ties. Requests without an Accept header get
+// JSON. HTML is only considered when html is true.
+func Negotiate(r *http.Request, html bool) (string, error) {
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return JSON, nil
+	}
+
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		format, ok := mediaTypes[strings.ToLower(strings.TrimSpace(mediaType))]
+		if !ok || (format == HTML && !html) {
+			continue
+		}
+		if quality := acceptQuality(params); quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+
+	if best == "" {
+		return "", ErrNotAcceptable
+	}
+	return best, nil
+}
+
+// acceptQuality reads the q parameter of an Accept entry, defaulting to 1
+func acceptQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || quality < 0 || quality > 1 {
+			return 0
+		}
+		return quality
+	}
+	return 1
+}
@@ -0,0 +1,123 @@
+// Package selector picks items from catalogs for the content widgets. It
+// supports random picks that avoid immediate repeats per client,
+// reproducible seeded picks and a stable pick per calendar day.
+package selector
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limits for the per-client history used to avoid repeats
+const (
+	maxTrackedClients = 10000
+	clientTTL         = time.Hour
+)
+
+// lastPick records the previous pick for one client and namespace
+type lastPick struct {
+	index int
+	at    time.Time
+}
+
+// Selector picks indexes into catalogs of a given size
+type Selector struct {
+	location *time.Location
+
+	mu   sync.Mutex
+	last map[string]lastPick
+}
+
+// New creates a selector whose daily picks change at midnight in location
+func New(location *time.Location) *Selector {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Selector{
+		location: location,
+		last:     make(map[string]lastPick),
+	}
+}
+
+// Random picks a random index in [0, n). The same client never gets the same
+// index twice in a row for a namespace, unless n is 1.
+func (s *Selector) Random(namespace, client string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	key := namespace + "|" + client
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var index int
+	if prev, ok := s.last[key]; ok && prev.index < n && now.Sub(prev.at) < clientTTL {
+		// Pick among the other n-1 items, skipping over the previous one
+		index = rand.IntN(n - 1)
+		if index >= prev.index {
+			index++
+		}
+	} else {
+		index = rand.IntN(n)
+	}
+
+	if len(s.last) >= maxTrackedClients {
+		s.prune(now)
+	}
+	s.last[key] = lastPick{index: index, at: now}
+
+	return index
+}
+
+// prune drops expired client entries, or all entries if none have expired.
+// Must be called with s.mu held.
+func (s *Selector) prune(now time.Time) {
+	for key, pick := range s.last {
+		if now.Sub(pick.at) >= clientTTL {
+			delete(s.last, key)
+		}
+	}
+	if len(s.last) >= maxTrackedClients {
+		s.last = make(map[string]lastPick)
+	}
+}
+
+// Seeded picks an index in [0, n) determined only by the seed, namespace and
+// n, so the same request always returns the same item
+func (s *Selector) Seeded(namespace, seed string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return int(hash(namespace, "seed", seed) % uint64(n))
+}
+
+// Daily picks an index in [0, n) that stays the same for the whole calendar
+// day containing t, in the selector's time zone
+func (s *Selector) Daily(namespace string, n int, t time.Time) int {
+	if n <= 1 {
+		return 0
+	}
+	return int(hash(namespace, "daily", t.In(s.location).Format("2006-01-02")) % uint64(n))
+}
+
+// Location returns the time zone used for daily picks
+func (s *Selector) Location() *time.Location {
+	return s.location
+}
+
+// hash combines the parts into a well-mixed 64-bit value
+func hash(parts ...string) uint64 {
+	h := fnv.New64a()
+	for _, part := range parts {
+		h.Write([]byte(strconv.Itoa(len(part))))
+		h.Write([]byte{':'})
+		h.Write([]byte(part))
+	}
+	// Run the FNV hash through a PCG step to spread nearby inputs
+	return rand.New(rand.NewPCG(h.Sum64(), 0)).Uint64()
+}
@@ -0,0 +1,195 @@
+//go:build linux
+
+package sysstats
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// linuxCollector reads statistics from /proc and statfs
+type linuxCollector struct {
+	diskPath string
+
+	prevCPUBusy  uint64
+	prevCPUTotal uint64
+	prevNetIn    uint64
+	prevNetOut   uint64
+	prevAt       time.Time
+	hasPrevCPU   bool
+	hasPrevNet   bool
+}
+
+func newCollector(diskPath string) collector {
+	return &linuxCollector{diskPath: diskPath}
+}
+
+func (l *linuxCollector) collect() Snapshot {
+	now := time.Now()
+	snapshot := Snapshot{SampledAt: now}
+
+	if busy, total, err := readCPUTimes(); err == nil {
+		if l.hasPrevCPU && total > l.prevCPUTotal && busy >= l.prevCPUBusy {
+			snapshot.CPU = Reading{
+				Value: float64(busy-l.prevCPUBusy) / float64(total-l.prevCPUTotal) * 100,
+				OK:    true,
+			}
+		}
+		l.prevCPUBusy, l.prevCPUTotal, l.hasPrevCPU = busy, total, true
+	} else {
+		l.hasPrevCPU = false
+	}
+
+	if usage, err := readMemoryUsage(); err == nil {
+		snapshot.Memory = Reading{Value: usage, OK: true}
+	}
+
+	if usage, err := readDiskUsage(l.diskPath); err == nil {
+		snapshot.Disk = Reading{Value: usage, OK: true}
+	}
+
+	if in, out, err := readNetworkBytes(); err == nil {
+		if elapsed := now.Sub(l.prevAt).Seconds(); l.hasPrevNet && elapsed > 0 && in >= l.prevNetIn && out >= l.prevNetOut {
+			snapshot.NetworkIn = Reading{Value: float64(in-l.prevNetIn) / elapsed, OK: true}
+			snapshot.NetworkOut = Reading{Value: float64(out-l.prevNetOut) / elapsed, OK: true}
+		}
+		l.prevNetIn, l.prevNetOut, l.hasPrevNet = in, out, true
+	} else {
+		l.hasPrevNet = false
+	}
+
+	l.prevAt = now
+	return snapshot
+}
+
+// readCPUTimes returns the busy and total jiffies from the aggregate cpu line
+// of /proc/stat
+func readCPUTimes() (busy, total uint64, err error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+
+		// user nice system idle iowait irq softirq steal guest guest_nice;
+		// guest time is already included in user and nice
+		var idle uint64
+		for i, field := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			total += v
+			if i == 3 || i == 4 {
+				idle += v
+			}
+		}
+		return total - idle, total, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	return 0, 0, os.ErrNotExist
+}
+
+// readMemoryUsage returns the percentage of memory in use from /proc/meminfo
+func readMemoryUsage() (float64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var total, available uint64
+	var hasTotal, hasAvailable bool
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total, err = strconv.ParseUint(fields[1], 10, 64)
+			hasTotal = err == nil
+		case "MemAvailable:":
+			available, err = strconv.ParseUint(fields[1], 10, 64)
+			hasAvailable = err == nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if !hasTotal || !hasAvailable || total == 0 {
+		return 0, os.ErrNotExist
+	}
+
+	return float64(total-available) / float64(total) * 100, nil
+}
+
+// readDiskUsage returns the percentage of the filesystem containing path in
+// use, as reported by df
+func readDiskUsage(path string) (float64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+
+	used := (st.Blocks - st.Bfree) * uint64(st.Bsize)
+	avail := st.Bavail * uint64(st.Bsize)
+	if used+avail == 0 {
+		return 0, os.ErrNotExist
+	}
+
+	return float64(used) / float64(used+avail) * 100, nil
+}
+
+// readNetworkBytes returns the total bytes received and sent by all
+// non-loopback interfaces from /proc/net/dev
+func readNetworkBytes() (in, out uint64, err error) {
+	f, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, counters, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(name) == "lo" {
+			continue
+		}
+
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+		rx, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		tx, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		in += rx
+		out += tx
+	}
+
+	return in, out, scanner.Err()
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package sysstats
+
+import "time"
+
+// unsupportedCollector reports every metric as unavailable
+type unsupportedCollector struct{}
+
+func newCollector(diskPath string) collector {
+	return unsupportedCollector{}
+}
+
+func (unsupportedCollector) collect() Snapshot {
+	return Snapshot{SampledAt: time.Now()}
+}
@@ -0,0 +1,85 @@
+package sysstats
+
+import (
+	"sync"
+	"time"
+)
+
+// Metric names accepted by Snapshot.Metric
+const (
+	MetricCPU        = "cpu"
+	MetricMemory     = "memory"
+	MetricDisk       = "disk"
+	MetricNetworkIn  = "network_in"
+	MetricNetworkOut = "network_out"
+)
+
+// Metrics lists every metric name in display order
+var Metrics = []string{MetricCPU, MetricMemory, MetricDisk, MetricNetworkIn, MetricNetworkOut}
+
+// Metric returns the reading for a metric name. The second result is false
+// for unknown names.
+func (s Snapshot) Metric(name string) (Reading, bool) {
+	switch name {
+	case MetricCPU:
+		return s.CPU, true
+	case MetricMemory:
+		return s.Memory, true
+	case MetricDisk:
+		return s.Disk, true
+	case MetricNetworkIn:
+		return s.NetworkIn, true
+	case MetricNetworkOut:
+		return s.NetworkOut, true
+	default:
+		return Reading{}, false
+	}
+}
+
+// History is a fixed-size ring buffer of snapshots. Once full, each new
+// snapshot overwrites the oldest one.
+type History struct {
+	mu   sync.RWMutex
+	buf  []Snapshot
+	next int
+	full bool
+}
+
+// NewHistory creates a history holding up to capacity snapshots
+func NewHistory(capacity int) *History {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &History{buf: make([]Snapshot, capacity)}
+}
+
+// Add records a snapshot
+func (h *History) Add(s Snapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf[h.next] = s
+	h.next = (h.next + 1) % len(h.buf)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Since returns the snapshots sampled at or after t, oldest first
+func (h *History) Since(t time.Time) []Snapshot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ordered := h.buf[:h.next]
+	if h.full {
+		ordered = append(append([]Snapshot{}, h.buf[h.next:]...), h.buf[:h.next]...)
+	}
+
+	result := make([]Snapshot, 0, len(ordered))
+	for _, s := range ordered {
+		if !s.SampledAt.Before(t) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
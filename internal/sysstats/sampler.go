@@ -0,0 +1,120 @@
+// Package sysstats samples host system statistics in the background
+package sysstats
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// firstSampleDelay is how long the sampler waits after its baseline reading
+// before taking the first reading that has CPU and network rates
+const firstSampleDelay = time.Second
+
+// Reading is a single metric value. OK is false when the metric could not be
+// read, for example on platforms without /proc.
+type Reading struct {
+	Value float64
+	OK    bool
+}
+
+// Snapshot holds one sample of the host statistics
+type Snapshot struct {
+	CPU        Reading // percent of total CPU time spent busy
+	Memory     Reading // percent of physical memory in use
+	Disk       Reading // percent of the sampled filesystem in use
+	NetworkIn  Reading // bytes received per second, all interfaces except loopback
+	NetworkOut Reading // bytes sent per second, all interfaces except loopback
+	SampledAt  time.Time
+}
+
+// collector reads the current statistics from the host. Rates are computed
+// against the previous call, so the first call reports them as unavailable.
+type collector interface {
+	collect() Snapshot
+}
+
+// Sampler periodically collects host statistics so readers never block on
+// the host. Samples are kept in a history covering the retention window.
+type Sampler struct {
+	interval  time.Duration
+	retention time.Duration
+	collector collector
+	history   *History
+
+	mu     sync.RWMutex
+	latest Snapshot
+}
+
+// NewSampler creates a sampler that reads host statistics every interval,
+// measuring disk usage of the filesystem containing diskPath and keeping
+// samples for the retention window
+func NewSampler(interval time.Duration, diskPath string, retention time.Duration) *Sampler {
+	return &Sampler{
+		interval:  interval,
+		retention: retention,
+		collector: newCollector(diskPath),
+		history:   NewHistory(int(retention/interval) + 1),
+	}
+}
+
+// Start takes a baseline reading and starts sampling in the background until
+// ctx is cancelled
+func (s *Sampler) Start(ctx context.Context) {
+	s.sample()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(firstSampleDelay):
+			s.sample()
+		}
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+}
+
+// Latest returns the most recent snapshot
+func (s *Sampler) Latest() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+// Interval returns the sampling interval
+func (s *Sampler) Interval() time.Duration {
+	return s.interval
+}
+
+// Retention returns how long samples are kept
+func (s *Sampler) Retention() time.Duration {
+	return s.retention
+}
+
+// History returns the recorded snapshots sampled at or after since, oldest
+// first
+func (s *Sampler) History(since time.Time) []Snapshot {
+	return s.history.Since(since)
+}
+
+// sample collects a snapshot, stores it as the latest and records it
+func (s *Sampler) sample() {
+	snapshot := s.collector.collect()
+
+	s.mu.Lock()
+	s.latest = snapshot
+	s.mu.Unlock()
+
+	s.history.Add(snapshot)
+}
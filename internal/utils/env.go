@@ -61,6 +61,59 @@ func GetEnvDuration(variable string, fallback time.Duration) (time.Duration, err
 	return duration, nil
 }
 
+// GetEnvFloat returns an environment variable as a number with a fallback
+func GetEnvFloat(variable string, fallback float64) (float64, error) {
+	value := os.Getenv(variable)
+	if value == "" {
+		return fallback, nil
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback, errors.New("environment variable " + variable + " is not a valid number: " + err.Error())
+	}
+	return floatValue, nil
+}
+
+// GetEnvList returns an environment variable as a comma-separated list with a
+// fallback. Items are trimmed and empty items are dropped.
+func GetEnvList(variable string, fallback []string) ([]string, error) {
+	value := os.Getenv(variable)
+	if value == "" {
+		return fallback, nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return fallback, errors.New("environment variable " + variable + " is not a valid list: no items")
+	}
+	return items, nil
+}
+
+// GetEnvFloatList returns an environment variable as a comma-separated list
+// of numbers with a fallback
+func GetEnvFloatList(variable string, fallback []float64) ([]float64, error) {
+	items, err := GetEnvList(variable, nil)
+	if err != nil || items == nil {
+		return fallback, err
+	}
+
+	values := make([]float64, len(items))
+	for i, item := range items {
+		value, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return fallback, errors.New("environment variable " + variable + " is not a valid list of numbers: " + err.Error())
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
 // Helper function to parse boolean values more flexibly
 func parseBool(value string) (bool, error) {
 	switch strings.ToLower(strings.TrimSpace(value)) {
@@ -3,12 +3,34 @@ package utils
 import (
 	"github.com/a-h/templ"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the spans around templ rendering
+var tracer = otel.Tracer("github.com/Damianko135/playground-go/internal/utils")
+
 // Temple wraps a templ.Component into an Echo handler
 func Temple(component templ.Component) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
-		return component.Render(c.Request().Context(), c.Response().Writer)
+		return Render(c, component)
+	}
+}
+
+// Render writes a templ component to the response inside a span, so
+// rendering time shows up in the request's trace
+func Render(c echo.Context, component templ.Component) error {
+	ctx, span := tracer.Start(c.Request().Context(), "templ.Render",
+		trace.WithAttributes(semconv.HTTPRoute(c.Path())))
+	defer span.End()
+
+	if err := component.Render(ctx, c.Response()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
+	return nil
 }